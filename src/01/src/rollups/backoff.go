@@ -0,0 +1,60 @@
+package rollups
+
+import (
+	"context"
+	"time"
+)
+
+// Backoff controls how long Run waits before calling /finish again after the
+// rollup server had nothing to process (202) or could not be reached. The
+// delay starts at Initial, grows by Multiplier after every consecutive idle
+// answer or transport error, is capped at Max and resets once a request
+// arrives. A zero Initial disables waiting.
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	// MaxRetries bounds the consecutive transport errors Run tolerates
+	// before giving up. Zero retries until ctx is cancelled.
+	MaxRetries int
+}
+
+var DefaultBackoff = Backoff{
+	Initial:    10 * time.Millisecond,
+	Max:        time.Second,
+	Multiplier: 2,
+}
+
+type backoffTimer struct {
+	Backoff
+	delay time.Duration
+}
+
+func (b *backoffTimer) reset() {
+	b.delay = 0
+}
+
+// wait sleeps for the next delay. It returns false if ctx is cancelled
+// first.
+func (b *backoffTimer) wait(ctx context.Context) bool {
+	switch {
+	case b.delay == 0:
+		b.delay = b.Initial
+	case b.Multiplier > 1:
+		b.delay = time.Duration(float64(b.delay) * b.Multiplier)
+	}
+	if b.Max > 0 && b.delay > b.Max {
+		b.delay = b.Max
+	}
+	if b.delay <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(b.delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
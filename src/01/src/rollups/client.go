@@ -0,0 +1,232 @@
+package rollups
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"dapp/rollups/codec"
+)
+
+// RetryPolicy controls how many times a request to the rollup server is
+// attempted and how long the client waits between attempts. Only transport
+// errors and 5xx responses are retried, and only on the report endpoint: a
+// failed notice, voucher or exception may still have been recorded by the
+// server, so sending it again could emit it twice. Finish is not retried
+// either, since a repeated finish would settle the next request; Run
+// recovers from failed finishes itself.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// NoRetry sends every request exactly once.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// Client talks to the rollup HTTP server exposed inside the Cartesi machine.
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	retryPolicy RetryPolicy
+	logger      *log.Logger
+}
+
+type ClientOption func(*Client)
+
+// WithBaseURL sets the rollup server URL, e.g. http://127.0.0.1:5004.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithHTTPClient replaces the underlying *http.Client, which is useful to
+// inject a custom transport in tests.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets the timeout of every single HTTP request. It is applied on
+// a copy of the current *http.Client, so pass it after WithHTTPClient.
+//
+// A finish that times out may still have been handled by the server, and
+// the next request it answered with is then lost: finishing again settles
+// that request without the application ever seeing it. Keep the timeout
+// above how long the server holds /finish while waiting for input.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		httpClient := *c.httpClient
+		httpClient.Timeout = timeout
+		c.httpClient = &httpClient
+	}
+}
+
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		if policy.MaxAttempts < 1 {
+			policy.MaxAttempts = 1
+		}
+		c.retryPolicy = policy
+	}
+}
+
+func WithLogger(logger *log.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// NewClient creates a client for the rollup server. By default it targets the
+// URL in ROLLUP_HTTP_SERVER_URL, uses http.DefaultClient and does not retry.
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL:     strings.TrimSuffix(os.Getenv("ROLLUP_HTTP_SERVER_URL"), "/"),
+		httpClient:  http.DefaultClient,
+		retryPolicy: NoRetry,
+		logger:      log.New(io.Discard, "", 0),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// SendPost posts jsonData to the given endpoint of the rollup server. Reports
+// are retried according to the client's RetryPolicy; any other endpoint is
+// tried once. The caller must close the body.
+func (c *Client) SendPost(ctx context.Context, endpoint string, jsonData []byte) (*http.Response, error) {
+	maxAttempts := 1
+	if idempotentEndpoints[endpoint] {
+		maxAttempts = c.retryPolicy.MaxAttempts
+	}
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			c.logger.Printf("retrying %s (attempt %d/%d): %v", endpoint, attempt, maxAttempts, lastErr)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(c.retryPolicy.Backoff):
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/"+endpoint, bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+		res, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+		if res.StatusCode >= http.StatusInternalServerError && attempt < maxAttempts {
+			res.Body.Close()
+			lastErr = fmt.Errorf("%s: unexpected status %d", endpoint, res.StatusCode)
+			continue
+		}
+		return res, nil
+	}
+	return nil, lastErr
+}
+
+// idempotentEndpoints are the endpoints SendPost may retry. Reports are not
+// proved on chain, so a duplicate costs nothing. Finish is left out: when the
+// server handled it but the answer was lost, a second finish settles the
+// next request before the application sees it.
+var idempotentEndpoints = map[string]bool{
+	"report": true,
+}
+
+func (c *Client) sendJSON(ctx context.Context, endpoint string, v any) (*http.Response, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return c.SendPost(ctx, endpoint, body)
+}
+
+func (c *Client) SendFinish(ctx context.Context, finish *FinishRequest) (*http.Response, error) {
+	return c.sendJSON(ctx, "finish", finish)
+}
+
+// SendReport emits a report. The rollup server does not index reports, so
+// only the outcome of the request is returned.
+func (c *Client) SendReport(ctx context.Context, report *ReportRequest) error {
+	return c.sendOutput(ctx, "report", report, nil)
+}
+
+// SendNotice emits a notice and returns its output index.
+func (c *Client) SendNotice(ctx context.Context, notice *NoticeRequest) (uint64, error) {
+	var index IndexResponse
+	if err := c.sendOutput(ctx, "notice", notice, &index); err != nil {
+		return 0, err
+	}
+	return index.Index, nil
+}
+
+// Notice emits payload as a notice and returns its output index.
+func (c *Client) Notice(ctx context.Context, payload []byte) (uint64, error) {
+	return c.SendNotice(ctx, &NoticeRequest{Payload: codec.EncodeHex(payload)})
+}
+
+// Report emits payload as a report.
+func (c *Client) Report(ctx context.Context, payload []byte) error {
+	return c.SendReport(ctx, &ReportRequest{Payload: codec.EncodeHex(payload)})
+}
+
+// SendVoucher emits a voucher and returns its output index.
+func (c *Client) SendVoucher(ctx context.Context, voucher *VoucherRequest) (uint64, error) {
+	var index IndexResponse
+	if err := c.sendOutput(ctx, "voucher", voucher, &index); err != nil {
+		return 0, err
+	}
+	return index.Index, nil
+}
+
+func (c *Client) SendException(ctx context.Context, exception *ExceptionRequest) error {
+	return c.sendOutput(ctx, "exception", exception, nil)
+}
+
+// sendOutput posts v to endpoint, turns non-2xx answers into *HTTPError and,
+// when out is not nil, decodes the response body into it.
+func (c *Client) sendOutput(ctx context.Context, endpoint string, v any, out any) error {
+	res, err := c.sendJSON(ctx, endpoint, v)
+	if err != nil {
+		return fmt.Errorf("rollups: %s: %w", endpoint, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return &HTTPError{
+			Endpoint:   endpoint,
+			StatusCode: res.StatusCode,
+			Body:       strings.TrimSpace(string(body)),
+		}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("rollups: %s: failed to decode response: %w", endpoint, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Package codec converts payloads to and from the 0x-prefixed hex strings
+// used by the rollup HTTP server API.
+package codec
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrMissingPrefix = errors.New("missing 0x prefix")
+	ErrOddLength     = errors.New("odd number of hex digits")
+	ErrInvalidDigit  = errors.New("invalid hex digit")
+)
+
+// HexError describes why a string could not be decoded. Err is one of
+// ErrMissingPrefix, ErrOddLength or ErrInvalidDigit, so callers can match
+// it with errors.Is.
+type HexError struct {
+	Input string
+	// Offset is the position of the offending character in Input, or -1
+	// when the error is not about a single character.
+	Offset int
+	Err    error
+}
+
+func (e *HexError) Error() string {
+	input := e.Input
+	if len(input) > 32 {
+		input = input[:32] + "..."
+	}
+	if e.Offset >= 0 {
+		return fmt.Sprintf("codec: %v at offset %d of %q", e.Err, e.Offset, input)
+	}
+	return fmt.Sprintf("codec: %v: %q", e.Err, input)
+}
+
+func (e *HexError) Unwrap() error {
+	return e.Err
+}
+
+// EncodeHex returns payload as a lowercase, 0x-prefixed hex string. An empty
+// payload encodes as "0x".
+func EncodeHex(payload []byte) string {
+	encoded := make([]byte, 2+hex.EncodedLen(len(payload)))
+	copy(encoded, "0x")
+	hex.Encode(encoded[2:], payload)
+	return string(encoded)
+}
+
+// DecodeHex decodes a 0x-prefixed hex string. The prefix is case-insensitive
+// and "0x" alone decodes to an empty, non-nil slice.
+func DecodeHex(input string) ([]byte, error) {
+	if len(input) < 2 || input[0] != '0' || (input[1] != 'x' && input[1] != 'X') {
+		return nil, &HexError{Input: input, Offset: -1, Err: ErrMissingPrefix}
+	}
+	digits := input[2:]
+	if len(digits)%2 != 0 {
+		return nil, &HexError{Input: input, Offset: -1, Err: ErrOddLength}
+	}
+	payload := make([]byte, len(digits)/2)
+	if _, err := hex.Decode(payload, []byte(digits)); err != nil {
+		var invalid hex.InvalidByteError
+		offset := -1
+		if errors.As(err, &invalid) {
+			for i := 0; i < len(digits); i++ {
+				if digits[i] == byte(invalid) {
+					offset = i + 2
+					break
+				}
+			}
+		}
+		return nil, &HexError{Input: input, Offset: offset, Err: ErrInvalidDigit}
+	}
+	return payload, nil
+}
@@ -1,79 +1,66 @@
 package rollups
 
 import (
-	"bytes"
-	"encoding/hex"
-	"encoding/json"
+	"context"
 	"net/http"
-	"os"
+
+	"dapp/rollups/codec"
 )
 
-var rollup_server = os.Getenv("ROLLUP_HTTP_SERVER_URL")
+var defaultClient = NewClient()
 
-func SendPost(endpoint string, jsonData []byte) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodPost, rollup_server+"/"+endpoint, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return &http.Response{}, err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+// DefaultClient returns the client used by the package-level Send* helpers.
+// It targets ROLLUP_HTTP_SERVER_URL as read at program start.
+func DefaultClient() *Client {
+	return defaultClient
+}
 
-	return http.DefaultClient.Do(req)
+func SendPost(endpoint string, jsonData []byte) (*http.Response, error) {
+	return defaultClient.SendPost(context.Background(), endpoint, jsonData)
 }
 
 func SendFinish(finish *FinishRequest) (*http.Response, error) {
-	body, err := json.Marshal(finish)
-	if err != nil {
-		return &http.Response{}, err
-	}
-
-	return SendPost("finish", body)
+	return defaultClient.SendFinish(context.Background(), finish)
 }
 
-func SendReport(report *ReportRequest) (*http.Response, error) {
-	body, err := json.Marshal(report)
-	if err != nil {
-		return &http.Response{}, err
-	}
-
-	return SendPost("report", body)
+func SendReport(report *ReportRequest) error {
+	return defaultClient.SendReport(context.Background(), report)
 }
 
-func SendNotice(notice *NoticeRequest) (*http.Response, error) {
-	body, err := json.Marshal(notice)
-	if err != nil {
-		return &http.Response{}, err
-	}
-
-	return SendPost("notice", body)
+func SendNotice(notice *NoticeRequest) (uint64, error) {
+	return defaultClient.SendNotice(context.Background(), notice)
 }
 
-func SendVoucher(voucher *VoucherRequest) (*http.Response, error) {
-	body, err := json.Marshal(voucher)
-	if err != nil {
-		return &http.Response{}, err
-	}
+func SendVoucher(voucher *VoucherRequest) (uint64, error) {
+	return defaultClient.SendVoucher(context.Background(), voucher)
+}
 
-	return SendPost("voucher", body)
+func SendException(exception *ExceptionRequest) error {
+	return defaultClient.SendException(context.Background(), exception)
 }
 
-func SendException(exception *ExceptionRequest) (*http.Response, error) {
-	body, err := json.Marshal(exception)
-	if err != nil {
-		return &http.Response{}, err
-	}
+func Notice(payload []byte) (uint64, error) {
+	return defaultClient.Notice(context.Background(), payload)
+}
 
-	return SendPost("exception", body)
+func Report(payload []byte) error {
+	return defaultClient.Report(context.Background(), payload)
 }
 
+// Hex2Str decodes a 0x-prefixed hex string into a string.
+//
+// Deprecated: use codec.DecodeHex, which keeps binary payloads as []byte.
 func Hex2Str(hx string) (string, error) {
-	str, err := hex.DecodeString(hx[2:])
+	payload, err := codec.DecodeHex(hx)
 	if err != nil {
-		return string(str), err
+		return "", err
 	}
-	return string(str), nil
+	return string(payload), nil
 }
 
+// Str2Hex encodes str as a 0x-prefixed hex string.
+//
+// Deprecated: use codec.EncodeHex.
 func Str2Hex(str string) string {
-	hx := hex.EncodeToString([]byte(str))
-	return "0x" + string(hx)
+	return codec.EncodeHex([]byte(str))
 }
@@ -0,0 +1,165 @@
+package rollups
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"dapp/rollups/codec"
+)
+
+const (
+	StatusAccept = "accept"
+	StatusReject = "reject"
+
+	RequestTypeAdvance = "advance_state"
+	RequestTypeInspect = "inspect_state"
+)
+
+// Application is the contract between a dApp and Run. Returning an error
+// rejects the input.
+type Application interface {
+	Advance(ctx context.Context, payload []byte, metadata Metadata) error
+	Inspect(ctx context.Context, payload []byte) error
+}
+
+type runConfig struct {
+	client  *Client
+	logger  *log.Logger
+	backoff Backoff
+}
+
+type RunOption func(*runConfig)
+
+// WithClient makes Run talk to the rollup server through client instead of
+// the default one.
+func WithClient(client *Client) RunOption {
+	return func(c *runConfig) {
+		c.client = client
+	}
+}
+
+func WithRunLogger(logger *log.Logger) RunOption {
+	return func(c *runConfig) {
+		c.logger = logger
+	}
+}
+
+// WithBackoff replaces DefaultBackoff as the policy Run follows between idle
+// or failed calls to /finish.
+func WithBackoff(backoff Backoff) RunOption {
+	return func(c *runConfig) {
+		c.backoff = backoff
+	}
+}
+
+// Run owns the finish loop: it asks the rollup server for the next request,
+// hands it to app and reports accept or reject back on the following finish.
+// It returns nil once ctx is cancelled. A request already being handled is
+// allowed to complete: handlers see a context that is not cancelled with ctx.
+//
+// Idle answers and transport errors are retried following the configured
+// Backoff, re-sending the same finish status so the outcome of the last
+// input is never lost.
+//
+// A *FatalError returned by app, or a finish response that cannot be
+// decoded, is reported through /exception and returned, halting the loop.
+func Run(ctx context.Context, app Application, opts ...RunOption) error {
+	cfg := &runConfig{
+		client:  defaultClient,
+		logger:  log.New(os.Stderr, "[ rollups ] ", log.Lshortfile),
+		backoff: DefaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	handlerCtx := context.WithoutCancel(ctx)
+	backoff := &backoffTimer{Backoff: cfg.backoff}
+	failures := 0
+	finish := FinishRequest{Status: StatusAccept}
+	for {
+		res, err := cfg.client.SendFinish(ctx, &finish)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			failures++
+			if cfg.backoff.MaxRetries > 0 && failures > cfg.backoff.MaxRetries {
+				return fmt.Errorf("rollups: finish: giving up after %d attempts: %w", failures, err)
+			}
+			cfg.logger.Printf("finish failed, retrying with status %q: %v", finish.Status, err)
+			if !backoff.wait(ctx) {
+				return nil
+			}
+			continue
+		}
+		failures = 0
+		if res.StatusCode == http.StatusAccepted {
+			res.Body.Close()
+			if !backoff.wait(ctx) {
+				return nil
+			}
+			continue
+		}
+		backoff.reset()
+		if res.StatusCode != http.StatusOK {
+			res.Body.Close()
+			return &HTTPError{Endpoint: "finish", StatusCode: res.StatusCode}
+		}
+
+		var response FinishResponse
+		err = json.NewDecoder(res.Body).Decode(&response)
+		res.Body.Close()
+		if err != nil {
+			return throw(handlerCtx, cfg.client, nil, Fatalf("rollups: finish: failed to decode response: %w", err))
+		}
+
+		finish.Status = StatusAccept
+		if err := handle(handlerCtx, app, &response); err != nil {
+			if IsFatal(err) {
+				return throw(handlerCtx, cfg.client, &response, err)
+			}
+			cfg.logger.Println(err)
+			finish.Status = StatusReject
+		}
+	}
+}
+
+func handle(ctx context.Context, app Application, response *FinishResponse) error {
+	switch response.Type {
+	case RequestTypeAdvance:
+		var data AdvanceResponse
+		if err := json.Unmarshal(response.Data, &data); err != nil {
+			return fmt.Errorf("rollups: failed to decode advance request: %w", err)
+		}
+		payload, err := decodePayload(data.Payload)
+		if err != nil {
+			return err
+		}
+		return app.Advance(ctx, payload, data.Metadata)
+	case RequestTypeInspect:
+		var data InspectResponse
+		if err := json.Unmarshal(response.Data, &data); err != nil {
+			return fmt.Errorf("rollups: failed to decode inspect request: %w", err)
+		}
+		payload, err := decodePayload(data.Payload)
+		if err != nil {
+			return err
+		}
+		return app.Inspect(ctx, payload)
+	default:
+		return fmt.Errorf("rollups: unknown request type: %q", response.Type)
+	}
+}
+
+func decodePayload(payload string) ([]byte, error) {
+	decoded, err := codec.DecodeHex(payload)
+	if err != nil {
+		return nil, fmt.Errorf("rollups: failed to decode payload: %w", err)
+	}
+	return decoded, nil
+}
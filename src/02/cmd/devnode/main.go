@@ -0,0 +1,90 @@
+// Command devnode is a local stand-in for the Cartesi node. It serves the
+// rollup HTTP server API that pkg/rollups applications talk to and a small
+// JSON API to drive them, so handlers can be run natively instead of inside
+// a Cartesi machine.
+//
+//	go run ./cmd/devnode
+//	ROLLUP_HTTP_SERVER_URL=http://127.0.0.1:5004 DATABASE_URL=memory:// go run ./cmd
+//
+//	curl -d '{"payload":{"path":"createToDo","payload":{"title":"t","description":"d"}}}' localhost:8080/advance
+//	curl localhost:8080/inspect/todos
+//	curl 'localhost:8080/outputs?type=notice'
+//
+// With -record, every request and the outputs it produced are appended to a
+// JSONL file that cmd/replay can play back. Rejected inputs are recorded too.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	// Registers the todo notice schemas, so /outputs shows them decoded.
+	_ "github.com/henriquemarlon/cartesi-golang-series/src/02/internal/usecase"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/rollupstest"
+)
+
+var (
+	infolog = log.New(os.Stderr, "[ info ] ", log.Lshortfile)
+	errlog  = log.New(os.Stderr, "[ error ] ", log.Lshortfile)
+)
+
+func main() {
+	rollupAddr := flag.String("rollup-addr", "127.0.0.1:5004", "address of the rollup HTTP server the application connects to")
+	apiAddr := flag.String("addr", "127.0.0.1:8080", "address of the devnode JSON API")
+	chainID := flag.Uint64("chain-id", 13370, "chain id sent in the input metadata")
+	appContract := flag.String("app-contract", "0xab7528bb862fb57e8a2bcd567a2e929a0be56a5e", "application address sent in the input metadata")
+	msgSender := flag.String("msg-sender", "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", "msg_sender used when an input does not set one")
+	record := flag.String("record", "", "file to append a JSONL recording of every request to")
+	timeout := flag.Duration("timeout", 30*time.Second, "how long to wait for the application to handle a request")
+	flag.Parse()
+	for _, address := range []string{*appContract, *msgSender} {
+		if !common.IsHexAddress(address) {
+			errlog.Fatalln("Invalid address", address)
+		}
+	}
+
+	server, err := rollupstest.Listen(*rollupAddr)
+	if err != nil {
+		errlog.Fatalln("Failed to start rollup server", "error", err)
+	}
+	defer server.Close()
+	server.Timeout = *timeout
+
+	config := Config{
+		ChainID:     *chainID,
+		AppContract: common.HexToAddress(*appContract),
+		MsgSender:   common.HexToAddress(*msgSender),
+	}
+	if *record != "" {
+		recording, err := os.OpenFile(*record, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			errlog.Fatalln("Failed to open recording", "error", err)
+		}
+		defer recording.Close()
+		config.Recording = recording
+		infolog.Println("Recording requests to", *record)
+	}
+	node := NewNode(server, config)
+	api := &http.Server{Addr: *apiAddr, Handler: node.Handler()}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		api.Shutdown(context.Background())
+	}()
+
+	infolog.Println("Rollup server listening on", server.URL)
+	infolog.Println("Devnode API listening on", "http://"+*apiAddr)
+	if err := api.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		errlog.Fatalln(err)
+	}
+}
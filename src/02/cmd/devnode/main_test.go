@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/cartesi/application"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository/in_memory"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/codec"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/rollupstest"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestDevNodeSuite(t *testing.T) {
+	suite.Run(t, new(DevNodeSuite))
+}
+
+type DevNodeSuite struct {
+	suite.Suite
+	server    *rollupstest.Server
+	api       *httptest.Server
+	cancel    context.CancelFunc
+	done      chan error
+	recording bytes.Buffer
+}
+
+func (s *DevNodeSuite) SetupTest() {
+	repo, err := in_memory.NewInMemoryRepository()
+	s.Require().NoError(err)
+
+	s.server, err = rollupstest.Listen("127.0.0.1:0")
+	s.Require().NoError(err)
+	s.recording.Reset()
+	s.api = httptest.NewServer(NewNode(s.server, Config{
+		ChainID:     13370,
+		AppContract: common.HexToAddress("0xab7528bb862fb57e8a2bcd567a2e929a0be56a5e"),
+		MsgSender:   common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+		Recording:   &s.recording,
+	}).Handler())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan error, 1)
+	go func() {
+		client := rollups.NewClient(rollups.WithBaseURL(s.server.URL))
+		s.done <- rollups.Run(ctx, application.NewToDoApplication(client, repo), rollups.WithClient(client))
+	}()
+}
+
+func (s *DevNodeSuite) TearDownTest() {
+	s.cancel()
+	s.NoError(<-s.done)
+	s.api.Close()
+	s.server.Close()
+}
+
+func (s *DevNodeSuite) post(path string, body string, out any) int {
+	res, err := http.Post(s.api.URL+path, "application/json", bytes.NewBufferString(body))
+	s.Require().NoError(err)
+	defer res.Body.Close()
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(out))
+	return res.StatusCode
+}
+
+func (s *DevNodeSuite) get(path string, out any) int {
+	res, err := http.Get(s.api.URL + path)
+	s.Require().NoError(err)
+	defer res.Body.Close()
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(out))
+	return res.StatusCode
+}
+
+func (s *DevNodeSuite) TestAdvanceAndInspect() {
+	var advance AdvanceResponse
+	status := s.post("/advance", `{
+		"msg_sender": "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		"block_timestamp": 1700000000,
+		"payload": {"path": "createToDo", "payload": {"title": "devnode", "description": "native"}}
+	}`, &advance)
+	s.Equal(http.StatusOK, status)
+	s.Equal(uint64(0), advance.InputIndex)
+	s.Equal(rollupstest.StatusAccept, advance.Status)
+	s.Require().Len(advance.Outputs, 1)
+	s.Equal(OutputNotice, advance.Outputs[0].Type)
+	s.Equal(
+		`ToDoCreated(1, 0x70997970C51812dc3A010C7d01b50e0d17dc79C8, "devnode", 1700000000)`,
+		advance.Outputs[0].Payload.Notice,
+	)
+
+	var inspect InspectResponse
+	status = s.get("/inspect/todos", &inspect)
+	s.Equal(http.StatusOK, status)
+	s.Equal(rollupstest.StatusAccept, inspect.Status)
+	s.Require().Len(inspect.Reports, 1)
+	s.Contains(inspect.Reports[0].Text, `"title":"devnode"`)
+}
+
+func (s *DevNodeSuite) TestOutputsKeepAcceptedInputsOnly() {
+	var advance AdvanceResponse
+	s.post("/advance", `{"payload": {"path": "createToDo", "payload": {"title": "first", "description": "d"}}}`, &advance)
+	s.post("/advance", `{"payload": "not json"}`, &advance)
+	s.Equal(uint64(1), advance.InputIndex)
+	s.Equal(rollupstest.StatusReject, advance.Status)
+	s.post("/advance", `{"payload": {"path": "createToDo", "payload": {"title": "second", "description": "d"}}}`, &advance)
+	s.Equal(uint64(2), advance.InputIndex)
+
+	var outputs []Output
+	s.get("/outputs?type=notice", &outputs)
+	s.Require().Len(outputs, 2)
+	s.Equal(uint64(0), outputs[0].InputIndex)
+	s.Equal(uint64(2), outputs[1].InputIndex)
+
+	s.get("/outputs?input_index=2", &outputs)
+	s.Len(outputs, 1)
+}
+
+func (s *DevNodeSuite) TestRecordingKeepsRejectedInputs() {
+	var advance AdvanceResponse
+	s.post("/advance", `{"payload": {"path": "createToDo", "payload": {"title": "first", "description": "d"}}}`, &advance)
+	s.post("/advance", `{"payload": "not json"}`, &advance)
+	s.Require().Equal(rollupstest.StatusReject, advance.Status)
+	var inspect InspectResponse
+	s.get("/inspect/todos", &inspect)
+
+	records, err := rollups.ReadRecording(&s.recording)
+	s.Require().NoError(err)
+	s.Require().Len(records, 3)
+	s.Equal(rollups.RequestTypeAdvance, records[0].Request.Type)
+	s.Equal(rollups.StatusAccept, records[0].Status)
+	s.Len(records[0].Notices, 1)
+	s.Equal(rollups.RequestTypeAdvance, records[1].Request.Type)
+	s.Equal(rollups.StatusReject, records[1].Status)
+	var data rollups.AdvanceResponse
+	s.Require().NoError(json.Unmarshal(records[1].Request.Data, &data))
+	s.Equal(codec.EncodeHex([]byte("not json")), data.Payload)
+	s.Equal(rollups.RequestTypeInspect, records[2].Request.Type)
+	s.Len(records[2].Reports, 1)
+}
+
+func (s *DevNodeSuite) TestInvalidHexPayload() {
+	var res map[string]string
+	status := s.post("/advance", `{"payload": "0xzz"}`, &res)
+	s.Equal(http.StatusBadRequest, status)
+	s.Contains(res["error"], "invalid payload")
+}
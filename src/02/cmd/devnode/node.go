@@ -0,0 +1,402 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/codec"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/rollupstest"
+)
+
+const (
+	OutputNotice              = "notice"
+	OutputVoucher             = "voucher"
+	OutputDelegateCallVoucher = "delegate_call_voucher"
+)
+
+// AdvanceRequest is the body of POST /advance. Payload is either a JSON
+// string, sent as UTF-8 unless it is 0x-prefixed hex, or any other JSON
+// value, sent verbatim. Zero metadata fields get the node's defaults.
+type AdvanceRequest struct {
+	MsgSender      common.Address  `json:"msg_sender"`
+	BlockNumber    uint64          `json:"block_number"`
+	BlockTimestamp uint64          `json:"block_timestamp"`
+	Payload        json.RawMessage `json:"payload"`
+}
+
+// InspectRequest is the body of POST /inspect, with Payload read as in
+// AdvanceRequest.
+type InspectRequest struct {
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Payload is an input or output payload as hex and, when it is valid UTF-8,
+// as text. Notice is set when the payload matches a schema registered in
+// rollups.Notices.
+type Payload struct {
+	Hex    string `json:"hex"`
+	Text   string `json:"text,omitempty"`
+	Notice string `json:"notice,omitempty"`
+}
+
+// Output is a notice or voucher kept by the node.
+type Output struct {
+	Type        string  `json:"type"`
+	InputIndex  uint64  `json:"input_index"`
+	Destination string  `json:"destination,omitempty"`
+	Value       string  `json:"value,omitempty"`
+	Payload     Payload `json:"payload"`
+}
+
+type AdvanceResponse struct {
+	InputIndex uint64    `json:"input_index"`
+	Status     string    `json:"status"`
+	Outputs    []Output  `json:"outputs"`
+	Reports    []Payload `json:"reports"`
+	Exception  *Payload  `json:"exception,omitempty"`
+}
+
+type InspectResponse struct {
+	Status  string    `json:"status"`
+	Reports []Payload `json:"reports"`
+}
+
+type Config struct {
+	ChainID     uint64
+	AppContract common.Address
+	MsgSender   common.Address
+	// Recording, if set, gets a rollups.Record line for every request the
+	// node sends, rejected inputs included. Unlike rollups.WithRecording it
+	// is written by the node, outside the application, so it is kept
+	// whatever the application state is rolled back to.
+	Recording io.Writer
+}
+
+// Node drives an application connected to its rollup server and keeps the
+// outputs it produces in memory.
+type Node struct {
+	config Config
+	server *rollupstest.Server
+
+	// advanceMu keeps input indexes in the order inputs reach the
+	// application.
+	advanceMu  sync.Mutex
+	mu         sync.RWMutex
+	inputIndex uint64
+	outputs    []Output
+	recording  *json.Encoder
+}
+
+func NewNode(server *rollupstest.Server, config Config) *Node {
+	node := &Node{config: config, server: server}
+	if config.Recording != nil {
+		node.recording = json.NewEncoder(config.Recording)
+	}
+	return node
+}
+
+func (n *Node) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /advance", n.handleAdvance)
+	mux.HandleFunc("POST /inspect", n.handleInspect)
+	mux.HandleFunc("GET /inspect/{path...}", n.handleInspectPath)
+	mux.HandleFunc("GET /outputs", n.handleOutputs)
+	return mux
+}
+
+func (n *Node) Advance(req *AdvanceRequest) (*AdvanceResponse, error) {
+	payload, err := decodePayload(req.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	n.advanceMu.Lock()
+	defer n.advanceMu.Unlock()
+
+	n.mu.RLock()
+	index := n.inputIndex
+	n.mu.RUnlock()
+
+	metadata := rollups.Metadata{
+		ChainID:        n.config.ChainID,
+		AppContract:    n.config.AppContract,
+		MsgSender:      req.MsgSender,
+		InputIndex:     index,
+		BlockNumber:    req.BlockNumber,
+		BlockTimestamp: req.BlockTimestamp,
+	}
+	if metadata.MsgSender == (common.Address{}) {
+		metadata.MsgSender = n.config.MsgSender
+	}
+	if metadata.BlockNumber == 0 {
+		metadata.BlockNumber = index + 1
+	}
+	if metadata.BlockTimestamp == 0 {
+		metadata.BlockTimestamp = uint64(time.Now().Unix())
+	}
+
+	data, err := json.Marshal(rollups.AdvanceResponse{
+		Metadata: metadata,
+		Payload:  codec.EncodeHex(payload),
+	})
+	if err != nil {
+		return nil, err
+	}
+	res, err := n.process(rollups.FinishResponse{Type: rollups.RequestTypeAdvance, Data: data})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &AdvanceResponse{
+		InputIndex: index,
+		Status:     res.Status,
+		Outputs:    []Output{},
+		Reports:    encodePayloads(res.Reports),
+	}
+	for _, notice := range res.Notices {
+		response.Outputs = append(response.Outputs, Output{
+			Type:       OutputNotice,
+			InputIndex: index,
+			Payload:    encodePayload(notice),
+		})
+	}
+	for _, voucher := range res.Vouchers {
+		response.Outputs = append(response.Outputs, Output{
+			Type:        OutputVoucher,
+			InputIndex:  index,
+			Destination: voucher.Destination,
+			Value:       voucher.Value,
+			Payload:     encodePayload(voucher.Payload),
+		})
+	}
+	for _, voucher := range res.DelegateCallVouchers {
+		response.Outputs = append(response.Outputs, Output{
+			Type:        OutputDelegateCallVoucher,
+			InputIndex:  index,
+			Destination: voucher.Destination,
+			Payload:     encodePayload(voucher.Payload),
+		})
+	}
+	if res.Exception != nil {
+		exception := encodePayload(res.Exception)
+		response.Exception = &exception
+	}
+
+	n.mu.Lock()
+	n.inputIndex++
+	// Outputs of rejected inputs are discarded, as on a real node.
+	if res.Status == rollupstest.StatusAccept {
+		n.outputs = append(n.outputs, response.Outputs...)
+	}
+	n.mu.Unlock()
+	return response, nil
+}
+
+func (n *Node) Inspect(req *InspectRequest) (*InspectResponse, error) {
+	payload, err := decodePayload(req.Payload)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(rollups.InspectResponse{Payload: codec.EncodeHex(payload)})
+	if err != nil {
+		return nil, err
+	}
+	res, err := n.process(rollups.FinishResponse{Type: rollups.RequestTypeInspect, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	return &InspectResponse{
+		Status:  res.Status,
+		Reports: encodePayloads(res.Reports),
+	}, nil
+}
+
+// process sends request to the application and records the result.
+func (n *Node) process(request rollups.FinishResponse) (*rollupstest.Result, error) {
+	res, err := n.server.Process(request)
+	if err != nil {
+		return nil, err
+	}
+	if n.recording == nil {
+		return res, nil
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if err := n.recording.Encode(newRecord(request, res)); err != nil {
+		return nil, fmt.Errorf("failed to write record: %w", err)
+	}
+	return res, nil
+}
+
+// newRecord turns res back into the output requests the application sent.
+func newRecord(request rollups.FinishResponse, res *rollupstest.Result) *rollups.Record {
+	record := &rollups.Record{Request: request, Status: res.Status}
+	for _, notice := range res.Notices {
+		record.Notices = append(record.Notices, rollups.NoticeRequest{Payload: codec.EncodeHex(notice)})
+	}
+	for _, report := range res.Reports {
+		record.Reports = append(record.Reports, rollups.ReportRequest{Payload: codec.EncodeHex(report)})
+	}
+	for _, voucher := range res.Vouchers {
+		record.Vouchers = append(record.Vouchers, rollups.VoucherRequest{
+			Destination: voucher.Destination,
+			Value:       voucher.Value,
+			Payload:     codec.EncodeHex(voucher.Payload),
+		})
+	}
+	for _, voucher := range res.DelegateCallVouchers {
+		record.DelegateCallVouchers = append(record.DelegateCallVouchers, rollups.DelegateCallVoucherRequest{
+			Destination: voucher.Destination,
+			Payload:     codec.EncodeHex(voucher.Payload),
+		})
+	}
+	if res.Exception != nil {
+		record.Exception = &rollups.ExceptionRequest{Payload: codec.EncodeHex(res.Exception)}
+	}
+	return record
+}
+
+// Outputs returns the outputs of accepted inputs, optionally filtered by
+// type and input index.
+func (n *Node) Outputs(outputType string, inputIndex *uint64) []Output {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	outputs := []Output{}
+	for _, output := range n.outputs {
+		if outputType != "" && output.Type != outputType {
+			continue
+		}
+		if inputIndex != nil && output.InputIndex != *inputIndex {
+			continue
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs
+}
+
+func (n *Node) handleAdvance(w http.ResponseWriter, r *http.Request) {
+	var req AdvanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	res, err := n.Advance(&req)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, res)
+}
+
+func (n *Node) handleInspect(w http.ResponseWriter, r *http.Request) {
+	var req InspectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	n.inspect(w, &req)
+}
+
+// handleInspectPath sends the URL path after /inspect/ as the inspect
+// payload, e.g. GET /inspect/todos/stats.
+func (n *Node) handleInspectPath(w http.ResponseWriter, r *http.Request) {
+	path, err := json.Marshal(r.PathValue("path"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	n.inspect(w, &InspectRequest{Payload: path})
+}
+
+func (n *Node) inspect(w http.ResponseWriter, req *InspectRequest) {
+	res, err := n.Inspect(req)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, res)
+}
+
+func (n *Node) handleOutputs(w http.ResponseWriter, r *http.Request) {
+	var inputIndex *uint64
+	if value := r.URL.Query().Get("input_index"); value != "" {
+		index, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid input_index: %w", err))
+			return
+		}
+		inputIndex = &index
+	}
+	writeJSON(w, n.Outputs(r.URL.Query().Get("type"), inputIndex))
+}
+
+var errInvalidPayload = errors.New("invalid payload")
+
+func decodePayload(raw json.RawMessage) ([]byte, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		// Not a JSON string: send the JSON value itself.
+		return raw, nil
+	}
+	if strings.HasPrefix(text, "0x") {
+		payload, err := codec.DecodeHex(text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidPayload, err)
+		}
+		return payload, nil
+	}
+	return []byte(text), nil
+}
+
+func encodePayload(payload []byte) Payload {
+	encoded := Payload{Hex: codec.EncodeHex(payload)}
+	if schema, args, err := rollups.Notices.Decode(payload); err == nil {
+		encoded.Notice = schema.Format(args)
+	} else if utf8.Valid(payload) {
+		encoded.Text = string(payload)
+	}
+	return encoded
+}
+
+func encodePayloads(payloads [][]byte) []Payload {
+	encoded := make([]Payload, 0, len(payloads))
+	for _, payload := range payloads {
+		encoded = append(encoded, encodePayload(payload))
+	}
+	return encoded
+}
+
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, errInvalidPayload):
+		return http.StatusBadRequest
+	case errors.Is(err, rollupstest.ErrTimeout):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -2,18 +2,17 @@ package main
 
 import (
 	"context"
-	"encoding/hex"
-	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"os"
+	"os/signal"
 	"strconv"
-	"time"
+	"syscall"
 
-	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/cartesi/handler/advance"
-	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/cartesi/handler/inspect"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/cartesi/application"
 	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository/factory"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/acl"
 	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
 )
 
@@ -22,93 +21,89 @@ var (
 	errlog  = log.New(os.Stderr, "[ error ] ", log.Lshortfile)
 )
 
-func DappStrategy(response *rollups.FinishResponse, router *rollups.Router, ih *inspect.ToDoInspectHandlers) error {
-	switch response.Type {
-	case "advance_state":
-		var data rollups.AdvanceResponse
-		if err := json.Unmarshal(response.Data, &data); err != nil {
-			return err
-		}
-		decodedPayload, err := hex.DecodeString(data.Payload[2:])
-		if err != nil {
-			return fmt.Errorf("handler: error decoding payload: %w", err)
-		}
-		return router.Advance(decodedPayload, data.Metadata)
-	case "inspect_state":
-		return ih.FindAllToDosHandler()
+func main() {
+	if err := run(); err != nil {
+		errlog.Fatalln(err)
 	}
-	return nil
+	infolog.Println("Shutdown complete")
 }
 
-func main() {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	// toDoRepository, err := factory.NewRepositoryFromConnectionString(ctx, "memory://")
-	// if err != nil {
-	// 	errlog.Panicln("Failed to initialize repository", "error", err)
-	// }
-
-	toDoRepository, err := factory.NewRepositoryFromConnectionString(ctx, "sqlite:///mnt/data/database.db")
-	if err != nil {
-		errlog.Panicln("Failed to initialize repository", "error", err)
+// run returns instead of exiting so that its deferred calls, which close the
+// repository and the recording, run before main exits.
+func run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// DATABASE_URL selects the repository, e.g. memory:// when running
+	// natively against cmd/devnode.
+	conn := os.Getenv("DATABASE_URL")
+	if conn == "" {
+		conn = "sqlite:///mnt/data/database.db"
 	}
 
-	// Router setup and handlers registration
-	defer toDoRepository.Close()
-
-	ah := advance.NewToDoAdvanceHandlers(toDoRepository)
+	// The repository outlives any single request, so it must not inherit a
+	// deadline or the shutdown signal: gorm keeps this context for every query.
+	toDoRepository, err := factory.NewRepositoryFromConnectionString(context.Background(), conn)
 	if err != nil {
-		errlog.Panicln("Failed to initialize advance handlers", "error", err)
+		return fmt.Errorf("failed to initialize repository: %w", err)
 	}
-	infolog.Println("Advance handlers initialized")
+	defer func() {
+		if err := toDoRepository.Close(); err != nil {
+			errlog.Println("Failed to close repository", "error", err)
+		}
+	}()
 
-	ih := inspect.NewToDoInspectHandlers(toDoRepository)
-	if err != nil {
-		errlog.Panicln("Failed to initialize inspect handlers", "error", err)
+	// ADMIN_ADDRESSES is a comma-separated list of accounts granted the admin
+	// role on the first start. It is part of the machine configuration, so
+	// every node bootstraps the same admins.
+	if value := os.Getenv("ADMIN_ADDRESSES"); value != "" {
+		admins, err := acl.ParseAccounts(value)
+		if err != nil {
+			return fmt.Errorf("invalid ADMIN_ADDRESSES: %w", err)
+		}
+		if err := acl.New(toDoRepository).Bootstrap(admins...); err != nil {
+			return err
+		}
 	}
-	infolog.Println("Inspect handlers initialized")
 
-	r := rollups.NewRouter()
-	r.HandleAdvance("createToDo", ah.CreateToDoHandler)
-	r.HandleAdvance("updateToDo", ah.UpdateToDoHandler)
-	r.HandleAdvance("deleteToDo", ah.DeleteToDoHandler)
-	infolog.Println("Router setup successful")
+	opts := []rollups.RunOption{rollups.WithBackoff(rollups.DefaultBackoff)}
 
-	// Polling loop ( Is there something new to process? )
-	finish := rollups.FinishRequest{
-		Status: "accept",
-	}
-	for {
-		infolog.Println("Sending finish")
-		res, err := rollups.SendFinish(&finish)
+	// Set RECORD_FILE to append every handled input and its outputs to a
+	// JSONL file that cmd/replay can play back. Only use it when running
+	// natively, e.g. against cmd/devnode: inside a Cartesi machine the file
+	// is machine state, so rejected inputs roll their records back and it
+	// cannot be read from outside. cmd/devnode -record records from the node
+	// side instead and keeps rejected inputs.
+	if path := os.Getenv("RECORD_FILE"); path != "" {
+		recording, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
 		if err != nil {
-			errlog.Panicln("Error: error making http request: ", err)
+			return fmt.Errorf("failed to open recording: %w", err)
 		}
-		infolog.Println("Received finish status ", strconv.Itoa(res.StatusCode))
-
-		if res.StatusCode == 202 {
-			infolog.Println("No pending rollup request, trying again")
-		} else {
-
-			resBody, err := io.ReadAll(res.Body)
-			if err != nil {
-				errlog.Panicln("Error: could not read response body: ", err)
-			}
-
-			var response rollups.FinishResponse
-			err = json.Unmarshal(resBody, &response)
-			if err != nil {
-				errlog.Panicln("Error: unmarshaling body:", err)
+		defer func() {
+			if err := recording.Close(); err != nil {
+				errlog.Println("Failed to close recording", "error", err)
 			}
-			finish.Status = "accept"
+		}()
+		opts = append(opts, rollups.WithRecording(recording))
+		infolog.Println("Recording inputs to", path)
+	}
 
-			// Strategy pattern to handle different types of requests (advance or inspect ?)
-			err = DappStrategy(&response, r, ih)
-			if err != nil {
-				errlog.Println(err)
-				finish.Status = "reject"
-			}
+	// CHAIN_ID and APP_CONTRACT, when set, make the application reject inputs
+	// meant for another deployment.
+	if value := os.Getenv("CHAIN_ID"); value != "" {
+		chainID, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid CHAIN_ID: %w", err)
 		}
+		opts = append(opts, rollups.WithChainID(chainID))
 	}
+	if value := os.Getenv("APP_CONTRACT"); value != "" {
+		if !common.IsHexAddress(value) {
+			return fmt.Errorf("invalid APP_CONTRACT %q", value)
+		}
+		opts = append(opts, rollups.WithAppContract(common.HexToAddress(value)))
+	}
+
+	app := application.NewToDoApplication(rollups.DefaultClient(), toDoRepository)
+	return rollups.Run(ctx, app, opts...)
 }
@@ -0,0 +1,205 @@
+// Command replay feeds a recording written by rollups.WithRecording or by
+// cmd/devnode -record back through the todo application, against a fresh
+// repository, and reports every request whose status or outputs differ from
+// the recorded ones.
+//
+//	go run ./cmd/replay -db memory:// recording.jsonl
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"unicode/utf8"
+
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/cartesi/application"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository/factory"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/codec"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/rollupstest"
+)
+
+var (
+	infolog = log.New(os.Stderr, "[ info ] ", 0)
+	errlog  = log.New(os.Stderr, "[ error ] ", 0)
+)
+
+func main() {
+	conn := flag.String("db", "memory://", "connection string of the repository to replay against; it should be empty")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-db conn] recording.jsonl\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	file, err := os.Open(flag.Arg(0))
+	if err != nil {
+		errlog.Fatalln(err)
+	}
+	records, err := rollups.ReadRecording(file)
+	file.Close()
+	if err != nil {
+		errlog.Fatalln(err)
+	}
+
+	mismatches, err := replay(*conn, records, os.Stdout)
+	if err != nil {
+		errlog.Fatalln(err)
+	}
+	if mismatches > 0 {
+		errlog.Printf("%d of %d requests differ from the recording", mismatches, len(records))
+		os.Exit(1)
+	}
+	infolog.Printf("%d requests replayed, no differences", len(records))
+}
+
+// replay runs records through a fresh application and writes the
+// differences to w. It returns how many records did not match.
+func replay(conn string, records []rollups.Record, w io.Writer) (int, error) {
+	repo, err := factory.NewRepositoryFromConnectionString(context.Background(), conn)
+	if err != nil {
+		return 0, fmt.Errorf("failed to initialize repository: %w", err)
+	}
+	defer repo.Close()
+
+	server := rollupstest.NewServer()
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		client := server.Client()
+		done <- rollups.Run(ctx, application.NewToDoApplication(client, repo), rollups.WithClient(client))
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	mismatches := 0
+	for i, record := range records {
+		res, err := server.Process(record.Request)
+		if err != nil {
+			return mismatches, fmt.Errorf("request %d: %w", i+1, err)
+		}
+		diffs := compare(&record, res)
+		if len(diffs) > 0 {
+			mismatches++
+			fmt.Fprintf(w, "request %d (%s):\n", i+1, record.Request.Type)
+			for _, diff := range diffs {
+				fmt.Fprintf(w, "\t%s\n", diff)
+			}
+		}
+		if res.Status == rollupstest.StatusException {
+			if i+1 < len(records) {
+				fmt.Fprintf(w, "application halted at request %d, %d requests not replayed\n", i+1, len(records)-i-1)
+				mismatches += len(records) - i - 1
+			}
+			break
+		}
+	}
+	return mismatches, nil
+}
+
+func compare(record *rollups.Record, res *rollupstest.Result) []string {
+	var diffs []string
+	if record.Status != res.Status {
+		diffs = append(diffs, fmt.Sprintf("status: recorded %s, replayed %s", record.Status, res.Status))
+	}
+
+	var notices, reports, vouchers, delegateCallVouchers []string
+	for _, notice := range record.Notices {
+		notices = append(notices, notice.Payload)
+	}
+	for _, report := range record.Reports {
+		reports = append(reports, report.Payload)
+	}
+	for _, voucher := range record.Vouchers {
+		vouchers = append(vouchers, voucher.Destination+" "+voucher.Value+" "+voucher.Payload)
+	}
+	for _, voucher := range record.DelegateCallVouchers {
+		delegateCallVouchers = append(delegateCallVouchers, voucher.Destination+" "+voucher.Payload)
+	}
+
+	var replayedVouchers, replayedDelegateCallVouchers []string
+	for _, voucher := range res.Vouchers {
+		replayedVouchers = append(replayedVouchers, voucher.Destination+" "+voucher.Value+" "+codec.EncodeHex(voucher.Payload))
+	}
+	for _, voucher := range res.DelegateCallVouchers {
+		replayedDelegateCallVouchers = append(replayedDelegateCallVouchers, voucher.Destination+" "+codec.EncodeHex(voucher.Payload))
+	}
+
+	diffs = append(diffs, comparePayloads("notice", notices, res.Notices)...)
+	diffs = append(diffs, comparePayloads("report", reports, res.Reports)...)
+	diffs = append(diffs, compareStrings("voucher", vouchers, replayedVouchers)...)
+	diffs = append(diffs, compareStrings("delegate call voucher", delegateCallVouchers, replayedDelegateCallVouchers)...)
+	if record.Exception != nil {
+		diffs = append(diffs, comparePayloads("exception", []string{record.Exception.Payload}, [][]byte{res.Exception})...)
+	} else if res.Exception != nil {
+		diffs = append(diffs, fmt.Sprintf("exception: not recorded, replayed %s", describe(res.Exception)))
+	}
+	return diffs
+}
+
+// comparePayloads compares hex-encoded recorded payloads with the raw ones
+// produced by the replay, showing both with describe.
+func comparePayloads(kind string, recorded []string, replayed [][]byte) []string {
+	var diffs []string
+	for i := 0; i < max(len(recorded), len(replayed)); i++ {
+		switch {
+		case i >= len(recorded):
+			diffs = append(diffs, fmt.Sprintf("%s %d: not recorded, replayed %s", kind, i, describe(replayed[i])))
+		case i >= len(replayed):
+			diffs = append(diffs, fmt.Sprintf("%s %d: recorded %s, not replayed", kind, i, decodeOrRaw(recorded[i])))
+		default:
+			want, err := codec.DecodeHex(recorded[i])
+			if err != nil || !bytes.Equal(want, replayed[i]) {
+				diffs = append(diffs, fmt.Sprintf("%s %d: recorded %s, replayed %s", kind, i, decodeOrRaw(recorded[i]), describe(replayed[i])))
+			}
+		}
+	}
+	return diffs
+}
+
+func compareStrings(kind string, recorded []string, replayed []string) []string {
+	var diffs []string
+	for i := 0; i < max(len(recorded), len(replayed)); i++ {
+		switch {
+		case i >= len(recorded):
+			diffs = append(diffs, fmt.Sprintf("%s %d: not recorded, replayed %s", kind, i, replayed[i]))
+		case i >= len(replayed):
+			diffs = append(diffs, fmt.Sprintf("%s %d: recorded %s, not replayed", kind, i, recorded[i]))
+		case recorded[i] != replayed[i]:
+			diffs = append(diffs, fmt.Sprintf("%s %d: recorded %s, replayed %s", kind, i, recorded[i], replayed[i]))
+		}
+	}
+	return diffs
+}
+
+func decodeOrRaw(payload string) string {
+	if decoded, err := codec.DecodeHex(payload); err == nil {
+		return describe(decoded)
+	}
+	return payload
+}
+
+// describe shows payload as a decoded notice if it matches one of the
+// registered schemas, as text if it is UTF-8, such as the JSON reports, and
+// as hex otherwise.
+func describe(payload []byte) string {
+	if schema, args, err := rollups.Notices.Decode(payload); err == nil {
+		return schema.Format(args)
+	}
+	if utf8.Valid(payload) {
+		return string(payload)
+	}
+	return codec.EncodeHex(payload)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/cartesi/application"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository/in_memory"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/usecase"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/codec"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/rollupstest"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestReplaySuite(t *testing.T) {
+	suite.Run(t, new(ReplaySuite))
+}
+
+type ReplaySuite struct {
+	suite.Suite
+	records []rollups.Record
+}
+
+// SetupTest records a short session of the todo application.
+func (s *ReplaySuite) SetupTest() {
+	repo, err := in_memory.NewInMemoryRepository()
+	s.Require().NoError(err)
+	server := rollupstest.NewServer()
+	defer server.Close()
+
+	var recording bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		client := server.Client()
+		done <- rollups.Run(ctx, application.NewToDoApplication(client, repo), rollups.WithClient(client), rollups.WithRecording(&recording))
+	}()
+
+	metadata := rollups.Metadata{
+		MsgSender:      common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"),
+		BlockTimestamp: 1700000000,
+	}
+	for _, input := range []struct {
+		path    string
+		payload any
+	}{
+		{"createToDo", usecase.CreateToDoInputDTO{Title: "first", Description: "description"}},
+		{"createToDo", usecase.CreateToDoInputDTO{Title: "", Description: "description"}},
+		{"deleteToDo", usecase.DeleteToDoInputDTO{Id: 1}},
+	} {
+		payload, err := json.Marshal(input.payload)
+		s.Require().NoError(err)
+		data, err := json.Marshal(rollups.Input{Path: input.path, Payload: payload})
+		s.Require().NoError(err)
+		_, err = server.Advance(data, metadata)
+		s.Require().NoError(err)
+	}
+	_, err = server.Inspect([]byte("todos"))
+	s.Require().NoError(err)
+	cancel()
+	s.Require().NoError(<-done)
+
+	s.records, err = rollups.ReadRecording(&recording)
+	s.Require().NoError(err)
+	s.Require().Len(s.records, 4)
+}
+
+func (s *ReplaySuite) TestReplayMatches() {
+	var out bytes.Buffer
+	mismatches, err := replay("memory://", s.records, &out)
+	s.NoError(err)
+	s.Equal(0, mismatches)
+	s.Empty(out.String())
+}
+
+func (s *ReplaySuite) TestReplayReportsDifferences() {
+	notice, err := usecase.ToDoCreatedNotice.Encode(big.NewInt(1), common.Address{}, "changed", uint64(1700000000))
+	s.Require().NoError(err)
+	s.records[0].Notices[0].Payload = codec.EncodeHex(notice)
+	s.records[1].Status = rollups.StatusAccept
+
+	var out bytes.Buffer
+	mismatches, err := replay("memory://", s.records, &out)
+	s.NoError(err)
+	s.Equal(2, mismatches)
+	s.Contains(out.String(), `notice 0: recorded ToDoCreated(1, 0x0000000000000000000000000000000000000000, "changed", 1700000000), `+
+		`replayed ToDoCreated(1, 0x70997970C51812dc3A010C7d01b50e0d17dc79C8, "first", 1700000000)`)
+	s.Contains(out.String(), "status: recorded accept, replayed reject")
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0 (see LICENSE)
+
+pragma solidity ^0.8.20;
+
+/// @notice Decodes the notices emitted by the todo application, as laid out
+/// by rollups.NoticeSchema: one version byte, the 4-byte selector of the
+/// notice signature and the ABI encoding of its arguments.
+/// @dev Only decode a notice once the application has validated it, e.g.
+/// application.validateOutput(abi.encodeCall(Outputs.Notice, (notice)), proof)
+/// after the epoch holding it is finalized.
+library ToDoNotices {
+    uint8 internal constant VERSION = 1;
+
+    bytes4 internal constant TODO_CREATED = bytes4(keccak256("ToDoCreated(uint256,address,string,uint64)"));
+    bytes4 internal constant TODO_UPDATED = bytes4(keccak256("ToDoUpdated(uint256,string,bool,uint64)"));
+    bytes4 internal constant TODO_DELETED = bytes4(keccak256("ToDoDeleted(uint256)"));
+    bytes4 internal constant TODO_RESTORED = bytes4(keccak256("ToDoRestored(uint256,uint64)"));
+
+    error UnexpectedNotice(uint8 version, bytes4 selector);
+
+    function decodeToDoCreated(bytes calldata notice)
+        internal
+        pure
+        returns (uint256 id, address owner, string memory title, uint64 createdAt)
+    {
+        return abi.decode(_args(notice, TODO_CREATED), (uint256, address, string, uint64));
+    }
+
+    function decodeToDoUpdated(bytes calldata notice)
+        internal
+        pure
+        returns (uint256 id, string memory title, bool completed, uint64 updatedAt)
+    {
+        return abi.decode(_args(notice, TODO_UPDATED), (uint256, string, bool, uint64));
+    }
+
+    function decodeToDoDeleted(bytes calldata notice) internal pure returns (uint256 id) {
+        return abi.decode(_args(notice, TODO_DELETED), (uint256));
+    }
+
+    function decodeToDoRestored(bytes calldata notice) internal pure returns (uint256 id, uint64 restoredAt) {
+        return abi.decode(_args(notice, TODO_RESTORED), (uint256, uint64));
+    }
+
+    function _args(bytes calldata notice, bytes4 selector) private pure returns (bytes calldata) {
+        if (notice.length < 5 || uint8(notice[0]) != VERSION || bytes4(notice[1:5]) != selector) {
+            revert UnexpectedNotice(notice.length > 0 ? uint8(notice[0]) : 0, notice.length < 5 ? bytes4(0) : bytes4(notice[1:5]));
+        }
+        return notice[5:];
+    }
+}
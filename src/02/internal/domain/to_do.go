@@ -3,24 +3,32 @@ package domain
 import (
 	"errors"
 	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 var (
 	ErrInvalidToDo = errors.New("invalid todo")
 	ErrNotFound    = errors.New("todo not found")
+	ErrNotOwner    = errors.New("todo not owned by sender")
 )
 
+// ToDo is never removed: deleting it sets DeletedAt to the block timestamp
+// of the deleting input, so it can be restored and its history kept.
 type ToDo struct {
-	Id          uint   `json:"id" gorm:"primaryKey"`
-	Title       string `json:"title" gorm:"type:text;not null"`
-	Description string `json:"description" gorm:"type:text;not null"`
-	Completed   bool   `json:"completed" gorm:"default:false"`
-	CreatedAt   uint64 `json:"created_at,omitempty" gorm:"not null"`
-	UpdatedAt   uint64 `json:"updated_at,omitempty" gorm:"default:0"`
+	Id          uint           `json:"id" gorm:"primaryKey"`
+	Owner       common.Address `json:"owner" gorm:"type:blob;index"`
+	Title       string         `json:"title" gorm:"type:text;not null"`
+	Description string         `json:"description" gorm:"type:text;not null"`
+	Completed   bool           `json:"completed" gorm:"default:false"`
+	CreatedAt   uint64         `json:"created_at,omitempty" gorm:"not null;autoCreateTime:false"`
+	UpdatedAt   uint64         `json:"updated_at,omitempty" gorm:"default:0;autoUpdateTime:false"`
+	DeletedAt   uint64         `json:"deleted_at,omitempty" gorm:"default:0;index"`
 }
 
-func NewToDo(title string, description string, createdAt uint64) (*ToDo, error) {
+func NewToDo(owner common.Address, title string, description string, createdAt uint64) (*ToDo, error) {
 	toDo := &ToDo{
+		Owner:       owner,
 		Title:       title,
 		Description: description,
 		CreatedAt:   createdAt,
@@ -31,6 +39,18 @@ func NewToDo(title string, description string, createdAt uint64) (*ToDo, error)
 	return toDo, nil
 }
 
+func (t *ToDo) IsDeleted() bool {
+	return t.DeletedAt != 0
+}
+
+// CheckOwner reports ErrNotOwner unless sender owns the todo.
+func (t *ToDo) CheckOwner(sender common.Address) error {
+	if t.Owner != sender {
+		return fmt.Errorf("%w: todo %d belongs to %s, not %s", ErrNotOwner, t.Id, t.Owner, sender)
+	}
+	return nil
+}
+
 func (t *ToDo) Validate() error {
 	if t.Title == "" {
 		return fmt.Errorf("%w: title cannot be empty", ErrInvalidToDo)
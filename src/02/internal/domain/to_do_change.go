@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ToDoAction is what an input did to a todo.
+type ToDoAction string
+
+const (
+	ToDoCreated  ToDoAction = "created"
+	ToDoUpdated  ToDoAction = "updated"
+	ToDoDeleted  ToDoAction = "deleted"
+	ToDoRestored ToDoAction = "restored"
+)
+
+// ToDoChange is an entry of a todo's append-only history: which input
+// changed it, who sent that input and which fields it changed.
+type ToDoChange struct {
+	Id         uint              `json:"id" gorm:"primaryKey"`
+	ToDoId     uint              `json:"todo_id" gorm:"index;not null"`
+	Action     ToDoAction        `json:"action" gorm:"not null"`
+	InputIndex uint64            `json:"input_index" gorm:"not null"`
+	MsgSender  common.Address    `json:"msg_sender" gorm:"type:blob"`
+	Timestamp  uint64            `json:"timestamp" gorm:"not null"`
+	Diff       []ToDoFieldChange `json:"diff" gorm:"serializer:json"`
+}
+
+// ToDoFieldChange holds the JSON values of a field before and after a
+// change. Before is null for fields set when the todo was created.
+type ToDoFieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// DiffToDos lists the fields that differ between before and after. A nil
+// before stands for a todo that did not exist yet, so the diff holds every
+// field set on creation with a null Before.
+func DiffToDos(before, after *ToDo) []ToDoFieldChange {
+	created := before == nil
+	if created {
+		before = &ToDo{}
+	}
+	fields := []struct {
+		name          string
+		before, after any
+	}{
+		{"owner", before.Owner, after.Owner},
+		{"title", before.Title, after.Title},
+		{"description", before.Description, after.Description},
+		{"completed", before.Completed, after.Completed},
+		{"deleted_at", before.DeletedAt, after.DeletedAt},
+	}
+	diff := []ToDoFieldChange{}
+	for _, field := range fields {
+		if field.before == field.after {
+			continue
+		}
+		change := ToDoFieldChange{Field: field.name, Before: json.RawMessage("null")}
+		if !created {
+			change.Before, _ = json.Marshal(field.before)
+		}
+		change.After, _ = json.Marshal(field.after)
+		diff = append(diff, change)
+	}
+	return diff
+}
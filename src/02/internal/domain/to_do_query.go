@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrInvalidQuery = errors.New("invalid todo query")
+
+// ToDoSort names the field todos are ordered by. Ties are always broken by
+// id so every page is deterministic.
+type ToDoSort string
+
+const (
+	SortToDosById        ToDoSort = "id"
+	SortToDosByCreatedAt ToDoSort = "created_at"
+	SortToDosByUpdatedAt ToDoSort = "updated_at"
+)
+
+// ToDoQuery selects a page of todos. Nil and zero fields do not filter;
+// timestamp ranges are inclusive. Deleted selects deleted todos instead of
+// live ones.
+type ToDoQuery struct {
+	Deleted     bool
+	Completed   *bool
+	Owner       *common.Address
+	CreatedFrom uint64
+	CreatedTo   uint64
+	UpdatedFrom uint64
+	UpdatedTo   uint64
+	SortBy      ToDoSort
+	Descending  bool
+	Offset      int
+	// Limit caps the page size; 0 returns every match.
+	Limit int
+}
+
+// ToDoPage is a page of todos and the number of todos matching the query
+// across all pages.
+type ToDoPage struct {
+	ToDos []*ToDo
+	Total int
+}
+
+func (q *ToDoQuery) Validate() error {
+	switch q.SortBy {
+	case "", SortToDosById, SortToDosByCreatedAt, SortToDosByUpdatedAt:
+	default:
+		return fmt.Errorf("%w: cannot sort by %q", ErrInvalidQuery, q.SortBy)
+	}
+	if q.Offset < 0 || q.Limit < 0 {
+		return fmt.Errorf("%w: offset and limit cannot be negative", ErrInvalidQuery)
+	}
+	if q.CreatedTo != 0 && q.CreatedFrom > q.CreatedTo {
+		return fmt.Errorf("%w: created range is empty", ErrInvalidQuery)
+	}
+	if q.UpdatedTo != 0 && q.UpdatedFrom > q.UpdatedTo {
+		return fmt.Errorf("%w: updated range is empty", ErrInvalidQuery)
+	}
+	return nil
+}
+
+// Match reports whether t passes the query filters.
+func (q *ToDoQuery) Match(t *ToDo) bool {
+	switch {
+	case t.IsDeleted() != q.Deleted:
+		return false
+	case q.Completed != nil && t.Completed != *q.Completed:
+		return false
+	case q.Owner != nil && t.Owner != *q.Owner:
+		return false
+	case t.CreatedAt < q.CreatedFrom || q.CreatedTo != 0 && t.CreatedAt > q.CreatedTo:
+		return false
+	case t.UpdatedAt < q.UpdatedFrom || q.UpdatedTo != 0 && t.UpdatedAt > q.UpdatedTo:
+		return false
+	}
+	return true
+}
+
+// Less reports whether a comes before b in the query order.
+func (q *ToDoQuery) Less(a, b *ToDo) bool {
+	var x, y uint64
+	switch q.SortBy {
+	case SortToDosByCreatedAt:
+		x, y = a.CreatedAt, b.CreatedAt
+	case SortToDosByUpdatedAt:
+		x, y = a.UpdatedAt, b.UpdatedAt
+	}
+	if x == y {
+		x, y = uint64(a.Id), uint64(b.Id)
+	}
+	if q.Descending {
+		return x > y
+	}
+	return x < y
+}
@@ -0,0 +1,74 @@
+package application
+
+import (
+	"log"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/cartesi/handler/advance"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/cartesi/handler/inspect"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/acl"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+)
+
+var infolog = log.New(os.Stderr, "[ info ] ", log.Lshortfile)
+
+// SignedInputDomain is the EIP-712 domain clients sign todo inputs under.
+var SignedInputDomain = rollups.SignedInputDomain{Name: "ToDo", Version: "1"}
+
+// NewToDoApplication wires the todo and wallet handlers into a router. It
+// lives outside cmd so that tools such as cmd/replay build the exact same
+// application.
+func NewToDoApplication(client *rollups.Client, repo repository.Repository) *rollups.Router {
+	// Router setup and handlers registration
+	ah := advance.NewToDoAdvanceHandlers(repo)
+	wah := advance.NewWalletAdvanceHandlers(repo)
+	infolog.Println("Advance handlers initialized")
+
+	ih := inspect.NewToDoInspectHandlers(repo, client)
+	wih := inspect.NewWalletInspectHandlers(repo, client)
+	infolog.Println("Inspect handlers initialized")
+
+	r := rollups.NewRouter()
+	r.Use(
+		rollups.Recover(),
+		rollups.Logger(slog.New(slog.NewTextHandler(os.Stderr, nil))),
+		rollups.Timing(func(path string, elapsed time.Duration) {
+			infolog.Printf("%s handled in %s", path, elapsed)
+		}),
+	)
+	// Users may sign JSON inputs and let a relayer pay for the InputBox call.
+	r.AcceptSignedInputs(SignedInputDomain, repo)
+	rollups.HandleJSON(r, "createToDo", ah.CreateToDoHandler)
+	rollups.HandleJSON(r, "updateToDo", ah.UpdateToDoHandler)
+	rollups.HandleJSON(r, "completeToDo", ah.CompleteToDoHandler)
+	rollups.HandleJSON(r, "reopenToDo", ah.ReopenToDoHandler)
+	rollups.HandleJSON(r, "deleteToDo", ah.DeleteToDoHandler)
+	rollups.HandleJSON(r, "restoreToDo", ah.RestoreToDoHandler)
+	r.HandleABI("createToDo(string,string)", ah.CreateToDoABIHandler)
+	r.HandleABI("updateToDo(uint256,string,string,bool)", ah.UpdateToDoABIHandler)
+	r.HandleABI("completeToDo(uint256)", ah.CompleteToDoABIHandler)
+	r.HandleABI("reopenToDo(uint256)", ah.ReopenToDoABIHandler)
+	r.HandleABI("deleteToDo(uint256)", ah.DeleteToDoABIHandler)
+	r.HandleABI("restoreToDo(uint256)", ah.RestoreToDoABIHandler)
+	r.HandleDeposit(wah.DepositHandler)
+	rollups.HandleJSON(r, "transfer", wah.TransferHandler)
+	rollups.HandleJSON(r, "withdraw", wah.WithdrawHandler)
+	r.HandleInspect("todos", ih.FindAllToDosHandler)
+	r.HandleInspect("todos/stats", ih.FindToDoStatsHandler)
+	r.HandleInspect("todos/query", ih.FindToDosHandler)
+	r.HandleInspect("todos/{id}", ih.FindToDoByIdHandler)
+	r.HandleInspect("todos/{id}/history", ih.FindToDoHistoryHandler)
+	r.HandleInspect("todos/owner/{owner}", ih.FindToDosByOwnerHandler)
+	r.HandleInspect("wallet/ether/{owner}", wih.EtherBalanceHandler)
+	r.HandleInspect("wallet/erc20/{token}/{owner}", wih.ERC20BalanceHandler)
+	r.HandleInspect("wallet/erc721/{token}/{token_id}", wih.ERC721OwnerHandler)
+	// Admins grant and revoke roles with grantRole and revokeRole; roles
+	// lists them.
+	acl.New(repo).Register(r)
+	infolog.Println("Router setup successful")
+
+	return r
+}
@@ -0,0 +1,610 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository/factory"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/usecase"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/rollupstest"
+	"github.com/stretchr/testify/suite"
+)
+
+var metadata = rollups.Metadata{
+	ChainID:        13370,
+	AppContract:    common.HexToAddress("0xab7528bb862fb57e8a2bcd567a2e929a0be56a5e"),
+	MsgSender:      common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"),
+	BlockNumber:    10,
+	BlockTimestamp: 1700000000,
+}
+
+func TestToDoApplicationSuite(t *testing.T) {
+	suite.Run(t, &ToDoApplicationSuite{conn: "memory://"})
+}
+
+// The sqlite run catches what the in-memory store hides, such as gorm
+// filling in timestamps on its own.
+func TestToDoApplicationSuiteSQLite(t *testing.T) {
+	suite.Run(t, &ToDoApplicationSuite{conn: "sqlite://"})
+}
+
+type ToDoApplicationSuite struct {
+	suite.Suite
+	conn   string
+	repo   repository.Repository
+	server *rollupstest.Server
+	cancel context.CancelFunc
+	done   chan error
+}
+
+func (s *ToDoApplicationSuite) SetupTest() {
+	conn := s.conn
+	if conn == "sqlite://" {
+		conn += filepath.Join(s.T().TempDir(), "todo.db")
+	}
+	repo, err := factory.NewRepositoryFromConnectionString(context.Background(), conn)
+	s.Require().NoError(err)
+	s.repo = repo
+
+	s.server = rollupstest.NewServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan error, 1)
+	go func() {
+		client := s.server.Client()
+		s.done <- rollups.Run(ctx, NewToDoApplication(client, repo), rollups.WithClient(client))
+	}()
+}
+
+func (s *ToDoApplicationSuite) TearDownTest() {
+	s.cancel()
+	s.NoError(<-s.done)
+	s.server.Close()
+	s.NoError(s.repo.Close())
+}
+
+func (s *ToDoApplicationSuite) advance(path string, payload any) *rollupstest.Result {
+	return s.advanceAs(metadata, path, payload)
+}
+
+func (s *ToDoApplicationSuite) advanceAs(metadata rollups.Metadata, path string, payload any) *rollupstest.Result {
+	data, err := json.Marshal(payload)
+	s.Require().NoError(err)
+	input, err := json.Marshal(rollups.Input{Path: path, Payload: data})
+	s.Require().NoError(err)
+	res, err := s.server.Advance(input, metadata)
+	s.Require().NoError(err)
+	return res
+}
+
+func (s *ToDoApplicationSuite) TestCreateToDo() {
+	res := s.advance("createToDo", usecase.CreateToDoInputDTO{
+		Title:       "write tests",
+		Description: "cover the todo app with rollupstest",
+	})
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Require().Len(res.Notices, 1)
+	args, err := usecase.ToDoCreatedNotice.Decode(res.Notices[0])
+	s.Require().NoError(err)
+	s.Equal([]any{big.NewInt(1), metadata.MsgSender, "write tests", uint64(1700000000)}, args)
+}
+
+func (s *ToDoApplicationSuite) TestCreateToDoWithABICalldata() {
+	method, err := rollups.ParseSignature("createToDo(string,string)")
+	s.Require().NoError(err)
+	calldata, err := method.Inputs.Pack("from calldata", "sent through the InputBox")
+	s.Require().NoError(err)
+
+	res, err := s.server.Advance(append(method.ID, calldata...), metadata)
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Require().Len(res.Notices, 1)
+
+	schema, args, err := rollups.Notices.Decode(res.Notices[0])
+	s.Require().NoError(err)
+	s.Equal(usecase.ToDoCreatedNotice, schema)
+	s.Equal("from calldata", args[2])
+}
+
+func (s *ToDoApplicationSuite) TestCreateInvalidToDo() {
+	res := s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "missing description"})
+	s.Equal(rollupstest.StatusReject, res.Status)
+	s.Empty(res.Notices)
+	s.Require().Len(res.Reports, 1)
+
+	var report rollups.ErrorReport
+	s.NoError(json.Unmarshal(res.Reports[0], &report))
+	s.Equal([]rollups.FieldError{{Field: "description", Tag: "required"}}, report.Fields)
+}
+
+func (s *ToDoApplicationSuite) TestUnknownPath() {
+	res := s.advance("archiveToDo", usecase.DeleteToDoInputDTO{Id: 1})
+	s.Equal(rollupstest.StatusReject, res.Status)
+}
+
+func (s *ToDoApplicationSuite) TestUpdateToDo() {
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "title", Description: "description"})
+	res := s.advance("updateToDo", map[string]any{"id": 1, "title": "new title", "completed": true})
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Require().Len(res.Notices, 1)
+
+	args, err := usecase.ToDoUpdatedNotice.Decode(res.Notices[0])
+	s.Require().NoError(err)
+	s.Equal([]any{big.NewInt(1), "new title", true, uint64(1700000000)}, args)
+	s.Equal("description", s.findToDo(1).Description)
+}
+
+// Notices must not depend on the node or backend that emitted them, so
+// updatedAt is the block timestamp of each update, on sqlite as well.
+func (s *ToDoApplicationSuite) TestUpdatedNoticeUsesBlockTimestamp() {
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "title", Description: "description"})
+	for i, timestamp := range []uint64{1700000100, 1700000200} {
+		update := metadata
+		update.InputIndex = uint64(i + 1)
+		update.BlockTimestamp = timestamp
+		res := s.advanceAs(update, "updateToDo", map[string]any{"id": 1, "completed": i == 0})
+		s.Equal(rollupstest.StatusAccept, res.Status)
+		s.Require().Len(res.Notices, 1)
+
+		args, err := usecase.ToDoUpdatedNotice.Decode(res.Notices[0])
+		s.Require().NoError(err)
+		s.Equal([]any{big.NewInt(1), "title", i == 0, timestamp}, args)
+		s.Equal(timestamp, s.findToDo(1).UpdatedAt)
+	}
+}
+
+func (s *ToDoApplicationSuite) TestPartialUpdateKeepsOtherFields() {
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "title", Description: "description"})
+	s.Equal(rollupstest.StatusAccept, s.advance("updateToDo", map[string]any{"id": 1, "completed": true}).Status)
+	s.Equal(rollupstest.StatusAccept, s.advance("updateToDo", map[string]any{"id": 1, "completed": false}).Status)
+
+	toDo := s.findToDo(1)
+	s.Equal("title", toDo.Title)
+	s.Equal("description", toDo.Description)
+	s.False(toDo.Completed)
+}
+
+func (s *ToDoApplicationSuite) TestCompleteAndReopenToDo() {
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "title", Description: "description"})
+	res := s.advance("completeToDo", usecase.ToggleToDoInputDTO{Id: 1})
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Require().Len(res.Notices, 1)
+	s.True(s.findToDo(1).Completed)
+
+	method, err := rollups.ParseSignature("reopenToDo(uint256)")
+	s.Require().NoError(err)
+	calldata, err := method.Inputs.Pack(big.NewInt(1))
+	s.Require().NoError(err)
+	res, err = s.server.Advance(append(method.ID, calldata...), metadata)
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.False(s.findToDo(1).Completed)
+}
+
+func (s *ToDoApplicationSuite) TestUpdateToDoRejectsInvalidMerge() {
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "title", Description: "description"})
+	s.Equal(rollupstest.StatusReject, s.advance("updateToDo", map[string]any{"id": 1, "title": ""}).Status)
+	s.Equal(rollupstest.StatusReject, s.advance("updateToDo", map[string]any{"id": 1}).Status)
+	s.Equal(rollupstest.StatusReject, s.advance("completeToDo", usecase.ToggleToDoInputDTO{Id: 42}).Status)
+	s.Equal("title", s.findToDo(1).Title)
+}
+
+func (s *ToDoApplicationSuite) TestOnlyOwnerChangesToDo() {
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "title", Description: "description"})
+	stranger := metadata
+	stranger.MsgSender = common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+
+	for path, payload := range map[string]any{
+		"updateToDo":   map[string]any{"id": 1, "title": "stolen"},
+		"completeToDo": usecase.ToggleToDoInputDTO{Id: 1},
+		"deleteToDo":   usecase.DeleteToDoInputDTO{Id: 1},
+	} {
+		res := s.advanceAs(stranger, path, payload)
+		s.Equal(rollupstest.StatusReject, res.Status, path)
+		s.Empty(res.Notices, path)
+	}
+	s.Equal("title", s.findToDo(1).Title)
+	s.Equal(rollupstest.StatusAccept, s.advance("deleteToDo", usecase.DeleteToDoInputDTO{Id: 1}).Status)
+}
+
+func (s *ToDoApplicationSuite) TestInspectToDosByOwner() {
+	other := metadata
+	other.MsgSender = common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "mine", Description: "description"})
+	s.advanceAs(other, "createToDo", usecase.CreateToDoInputDTO{Title: "theirs", Description: "description"})
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "mine too", Description: "description"})
+
+	res, err := s.server.Inspect([]byte("todos/owner/" + metadata.MsgSender.Hex()))
+	s.Require().NoError(err)
+	s.Require().Len(res.Reports, 1)
+	var toDos usecase.FindAllToDosOutputDTO
+	s.NoError(json.Unmarshal(res.Reports[0], &toDos))
+	s.Require().Len(toDos, 2)
+	s.Equal("mine", toDos[0].Title)
+	s.Equal("mine too", toDos[1].Title)
+	s.Equal(metadata.MsgSender, toDos[1].Owner)
+
+	res, err = s.server.Inspect([]byte("todos/owner/not-an-address"))
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusReject, res.Status)
+	s.Require().Len(res.Reports, 1)
+	var report rollups.ErrorReport
+	s.NoError(json.Unmarshal(res.Reports[0], &report))
+	s.Equal(`invalid owner address: "not-an-address"`, report.Error)
+}
+
+func (s *ToDoApplicationSuite) TestQueryToDos() {
+	other := common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+	for i, title := range []string{"a", "b", "c", "d", "e"} {
+		at := metadata
+		at.BlockTimestamp = uint64(100 * (i + 1))
+		if i%2 == 1 {
+			at.MsgSender = other
+		}
+		s.advanceAs(at, "createToDo", usecase.CreateToDoInputDTO{Title: title, Description: "description"})
+	}
+	s.advance("completeToDo", usecase.ToggleToDoInputDTO{Id: 3})
+
+	page := s.queryToDos(`{}`)
+	s.Equal(5, page.Total)
+	s.Equal(usecase.DefaultToDoPageSize, page.Limit)
+	s.Equal([]string{"a", "b", "c", "d", "e"}, titles(page))
+
+	page = s.queryToDos(`{"sort_by":"created_at","order":"desc","offset":1,"limit":2}`)
+	s.Equal(5, page.Total)
+	s.Equal([]string{"d", "c"}, titles(page))
+
+	page = s.queryToDos(`{"owner":"` + other.Hex() + `"}`)
+	s.Equal([]string{"b", "d"}, titles(page))
+
+	page = s.queryToDos(`{"completed":false,"created_from":200,"created_to":400}`)
+	s.Equal(2, page.Total)
+	s.Equal([]string{"b", "d"}, titles(page))
+
+	page = s.queryToDos(`{"updated_from":1,"sort_by":"updated_at"}`)
+	s.Equal([]string{"c"}, titles(page))
+}
+
+func (s *ToDoApplicationSuite) TestQueryToDosRejectsBadQuery() {
+	for _, query := range []string{
+		`{"sort_by":"title"}`,
+		`{"order":"sideways"}`,
+		`{"limit":1000}`,
+		`{"offset":-1}`,
+		`{"created_from":5,"created_to":1}`,
+	} {
+		res, err := s.server.Inspect([]byte(`{"path":"todos/query","payload":` + query + `}`))
+		s.Require().NoError(err)
+		s.Equal(rollupstest.StatusReject, res.Status, query)
+		s.Require().Len(res.Reports, 1)
+		s.Contains(string(res.Reports[0]), "invalid todo query", query)
+	}
+}
+
+func (s *ToDoApplicationSuite) queryToDos(query string) usecase.FindToDosOutputDTO {
+	res, err := s.server.Inspect([]byte(`{"path":"todos/query","payload":` + query + `}`))
+	s.Require().NoError(err)
+	s.Require().Equal(rollupstest.StatusAccept, res.Status)
+	s.Require().Len(res.Reports, 1)
+	var page usecase.FindToDosOutputDTO
+	s.Require().NoError(json.Unmarshal(res.Reports[0], &page))
+	return page
+}
+
+func titles(page usecase.FindToDosOutputDTO) []string {
+	titles := make([]string, len(page.ToDos))
+	for i, toDo := range page.ToDos {
+		titles[i] = toDo.Title
+	}
+	return titles
+}
+
+func (s *ToDoApplicationSuite) findToDo(id uint) usecase.FindToDoOutputDTO {
+	res, err := s.server.Inspect([]byte("todos"))
+	s.Require().NoError(err)
+	s.Require().Len(res.Reports, 1)
+	var toDos usecase.FindAllToDosOutputDTO
+	s.Require().NoError(json.Unmarshal(res.Reports[0], &toDos))
+	for _, toDo := range toDos {
+		if toDo.Id == id {
+			return *toDo
+		}
+	}
+	s.FailNow("todo not found", "id %d", id)
+	return usecase.FindToDoOutputDTO{}
+}
+
+func (s *ToDoApplicationSuite) TestSignedInputFromRelayer() {
+	key, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	payload, err := json.Marshal(usecase.CreateToDoInputDTO{Title: "relayed", Description: "signed off-chain"})
+	s.Require().NoError(err)
+	input := &rollups.SignedInput{Path: "createToDo", Payload: payload}
+	s.Require().NoError(SignedInputDomain.Sign(key, metadata.ChainID, metadata.AppContract, input))
+	data, err := json.Marshal(input)
+	s.Require().NoError(err)
+
+	res, err := s.server.Advance(data, metadata)
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Require().Len(res.Notices, 1)
+	args, err := usecase.ToDoCreatedNotice.Decode(res.Notices[0])
+	s.Require().NoError(err)
+	s.Equal(crypto.PubkeyToAddress(key.PublicKey), args[1])
+
+	res, err = s.server.Advance(data, metadata)
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusReject, res.Status)
+}
+
+func (s *ToDoApplicationSuite) TestDeleteToDo() {
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "title", Description: "description"})
+	res := s.advance("deleteToDo", usecase.DeleteToDoInputDTO{Id: 1})
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Require().Len(res.Notices, 1)
+	args, err := usecase.ToDoDeletedNotice.Decode(res.Notices[0])
+	s.Require().NoError(err)
+	s.Equal([]any{big.NewInt(1)}, args)
+}
+
+func (s *ToDoApplicationSuite) TestDeleteAndRestoreToDo() {
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "title", Description: "description"})
+	s.advance("deleteToDo", usecase.DeleteToDoInputDTO{Id: 1})
+
+	res, err := s.server.Inspect([]byte("todos/1"))
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusReject, res.Status)
+	s.Empty(s.queryToDos(`{}`).ToDos)
+	deleted := s.queryToDos(`{"deleted":true}`)
+	s.Equal([]string{"title"}, titles(deleted))
+	s.Equal(metadata.BlockTimestamp, deleted.ToDos[0].DeletedAt)
+	s.Zero(deleted.ToDos[0].UpdatedAt)
+	s.Equal(rollupstest.StatusReject, s.advance("completeToDo", usecase.ToggleToDoInputDTO{Id: 1}).Status)
+	s.Equal(rollupstest.StatusReject, s.advance("deleteToDo", usecase.DeleteToDoInputDTO{Id: 1}).Status)
+
+	restore := metadata
+	restore.BlockTimestamp = 1700000100
+	res = s.advanceAs(restore, "restoreToDo", usecase.RestoreToDoInputDTO{Id: 1})
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Require().Len(res.Notices, 1)
+	args, err := usecase.ToDoRestoredNotice.Decode(res.Notices[0])
+	s.Require().NoError(err)
+	s.Equal([]any{big.NewInt(1), uint64(1700000100)}, args)
+
+	toDo := s.findToDo(1)
+	s.Equal("title", toDo.Title)
+	s.Zero(toDo.DeletedAt)
+	s.Equal(uint64(1700000100), toDo.UpdatedAt)
+	s.Equal(rollupstest.StatusReject, s.advance("restoreToDo", usecase.RestoreToDoInputDTO{Id: 1}).Status)
+}
+
+func (s *ToDoApplicationSuite) TestOnlyOwnerRestoresToDo() {
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "title", Description: "description"})
+	s.advance("deleteToDo", usecase.DeleteToDoInputDTO{Id: 1})
+	stranger := metadata
+	stranger.MsgSender = common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+	s.Equal(rollupstest.StatusReject, s.advanceAs(stranger, "restoreToDo", usecase.RestoreToDoInputDTO{Id: 1}).Status)
+}
+
+func (s *ToDoApplicationSuite) TestToDoHistory() {
+	at := func(index, timestamp uint64) rollups.Metadata {
+		m := metadata
+		m.InputIndex = index
+		m.BlockTimestamp = timestamp
+		return m
+	}
+	s.advanceAs(at(0, 100), "createToDo", usecase.CreateToDoInputDTO{Title: "title", Description: "description"})
+	s.advanceAs(at(1, 200), "updateToDo", map[string]any{"id": 1, "title": "new title", "completed": true})
+	s.advanceAs(at(2, 300), "deleteToDo", usecase.DeleteToDoInputDTO{Id: 1})
+	s.advanceAs(at(3, 400), "updateToDo", map[string]any{"id": 1, "title": "rejected"})
+	s.advanceAs(at(4, 500), "restoreToDo", usecase.RestoreToDoInputDTO{Id: 1})
+
+	res, err := s.server.Inspect([]byte("todos/1/history"))
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Require().Len(res.Reports, 1)
+	s.JSONEq(`[
+		{"action":"created","input_index":0,"msg_sender":"0x70997970c51812dc3a010c7d01b50e0d17dc79c8","timestamp":100,"diff":[
+			{"field":"owner","before":null,"after":"0x70997970c51812dc3a010c7d01b50e0d17dc79c8"},
+			{"field":"title","before":null,"after":"title"},
+			{"field":"description","before":null,"after":"description"}
+		]},
+		{"action":"updated","input_index":1,"msg_sender":"0x70997970c51812dc3a010c7d01b50e0d17dc79c8","timestamp":200,"diff":[
+			{"field":"title","before":"title","after":"new title"},
+			{"field":"completed","before":false,"after":true}
+		]},
+		{"action":"deleted","input_index":2,"msg_sender":"0x70997970c51812dc3a010c7d01b50e0d17dc79c8","timestamp":300,"diff":[
+			{"field":"deleted_at","before":0,"after":300}
+		]},
+		{"action":"restored","input_index":4,"msg_sender":"0x70997970c51812dc3a010c7d01b50e0d17dc79c8","timestamp":500,"diff":[
+			{"field":"deleted_at","before":300,"after":0}
+		]}
+	]`, string(res.Reports[0]))
+
+	res, err = s.server.Inspect([]byte("todos/7/history"))
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusReject, res.Status)
+	s.Require().Len(res.Reports, 1)
+	s.Contains(string(res.Reports[0]), "todo not found")
+}
+
+func (s *ToDoApplicationSuite) TestMalformedPayload() {
+	input, err := json.Marshal(rollups.Input{Path: "createToDo", Payload: json.RawMessage(`["not","an","object"]`)})
+	s.Require().NoError(err)
+	res, err := s.server.Advance(input, metadata)
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusReject, res.Status)
+	s.Require().Len(res.Reports, 1)
+	s.Contains(string(res.Reports[0]), "invalid payload")
+}
+
+func (s *ToDoApplicationSuite) TestDeleteMissingToDo() {
+	res := s.advance("deleteToDo", usecase.DeleteToDoInputDTO{Id: 42})
+	s.Equal(rollupstest.StatusReject, res.Status)
+	s.Empty(res.Notices)
+}
+
+func (s *ToDoApplicationSuite) TestInspectToDos() {
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "title", Description: "description"})
+	res, err := s.server.Inspect([]byte(`{"path":"todos"}`))
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Require().Len(res.Reports, 1)
+
+	var toDos usecase.FindAllToDosOutputDTO
+	s.NoError(json.Unmarshal(res.Reports[0], &toDos))
+	s.Len(toDos, 1)
+	s.Equal("title", toDos[0].Title)
+}
+
+func (s *ToDoApplicationSuite) TestInspectToDoById() {
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "first", Description: "description"})
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "second", Description: "description"})
+	res, err := s.server.Inspect([]byte("todos/2"))
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Require().Len(res.Reports, 1)
+
+	var toDo usecase.FindToDoOutputDTO
+	s.NoError(json.Unmarshal(res.Reports[0], &toDo))
+	s.Equal(uint(2), toDo.Id)
+	s.Equal("second", toDo.Title)
+}
+
+func (s *ToDoApplicationSuite) TestInspectMissingToDo() {
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "title", Description: "description"})
+	for _, path := range []string{"todos/42", "todos/0", "todos/-1"} {
+		res, err := s.server.Inspect([]byte(path))
+		s.Require().NoError(err)
+		s.Equal(rollupstest.StatusReject, res.Status, path)
+		s.Require().Len(res.Reports, 1, path)
+
+		var report rollups.ErrorReport
+		s.NoError(json.Unmarshal(res.Reports[0], &report))
+		s.Contains(report.Error, "todo not found", path)
+	}
+}
+
+func (s *ToDoApplicationSuite) TestInspectToDoStats() {
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "first", Description: "description"})
+	s.advance("createToDo", usecase.CreateToDoInputDTO{Title: "second", Description: "description"})
+	s.advance("completeToDo", usecase.ToggleToDoInputDTO{Id: 1})
+	res, err := s.server.Inspect([]byte("todos/stats"))
+	s.Require().NoError(err)
+	s.Require().Len(res.Reports, 1)
+	s.JSONEq(`{"total":2,"completed":1,"pending":1}`, string(res.Reports[0]))
+}
+
+func (s *ToDoApplicationSuite) TestInspectUnknownPath() {
+	res, err := s.server.Inspect([]byte("users"))
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusReject, res.Status)
+	s.Require().Len(res.Reports, 1)
+	s.JSONEq(`{"error":"handler: path not found: users"}`, string(res.Reports[0]))
+}
+
+func (s *ToDoApplicationSuite) TestEtherDepositTransferAndWithdraw() {
+	sender := metadata.MsgSender
+	recipient := common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+
+	deposit := metadata
+	deposit.MsgSender = rollups.DefaultPortalConfig().EtherPortal
+	payload := append(sender.Bytes(), common.LeftPadBytes(big.NewInt(1000).Bytes(), 32)...)
+	res, err := s.server.Advance(payload, deposit)
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusAccept, res.Status)
+
+	res = s.advance("transfer", usecase.TransferAssetInputDTO{Asset: usecase.AssetEther, To: recipient, Value: big.NewInt(300)})
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Require().Len(res.Notices, 1)
+
+	res = s.advance("withdraw", usecase.WithdrawAssetInputDTO{Asset: usecase.AssetEther, Value: big.NewInt(800)})
+	s.Equal(rollupstest.StatusReject, res.Status)
+	s.Empty(res.Vouchers)
+
+	res = s.advance("withdraw", usecase.WithdrawAssetInputDTO{Asset: usecase.AssetEther, Value: big.NewInt(700)})
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Require().Len(res.Vouchers, 1)
+	s.Equal(sender.Hex(), res.Vouchers[0].Destination)
+
+	res, err = s.server.Inspect([]byte("wallet/ether/" + recipient.Hex()))
+	s.Require().NoError(err)
+	s.Require().Len(res.Reports, 1)
+	var balance usecase.FindBalanceOutputDTO
+	s.NoError(json.Unmarshal(res.Reports[0], &balance))
+	s.Equal(big.NewInt(300), balance.Balance)
+}
+
+func (s *ToDoApplicationSuite) TestInspectInvalidWalletPaths() {
+	token := "0xFBdB734EF6a23aD76863CbA6f10d0C5CBBD8342C"
+	for path, want := range map[string]string{
+		"wallet/ether/0x12":                       `invalid owner address: "0x12"`,
+		"wallet/erc20/0x12/" + token:              `invalid token address: "0x12"`,
+		"wallet/erc20/" + token + "/0x12":         `invalid owner address: "0x12"`,
+		"wallet/erc721/0x12/1":                    `invalid token address: "0x12"`,
+		"wallet/erc721/" + token + "/not-a-token": `invalid token id: "not-a-token"`,
+	} {
+		res, err := s.server.Inspect([]byte(path))
+		s.Require().NoError(err)
+		s.Equal(rollupstest.StatusReject, res.Status, path)
+		s.Require().Len(res.Reports, 1, path)
+
+		var report rollups.ErrorReport
+		s.NoError(json.Unmarshal(res.Reports[0], &report))
+		s.Equal(want, report.Error, path)
+	}
+}
+
+func (s *ToDoApplicationSuite) TestDepositWithInvalidExecLayerData() {
+	sender := metadata.MsgSender
+	deposit := metadata
+	deposit.MsgSender = rollups.DefaultPortalConfig().EtherPortal
+	payload := append(sender.Bytes(), common.LeftPadBytes(big.NewInt(1000).Bytes(), 32)...)
+	payload = append(payload, `{"path":"createToDo","payload":{"title":"no description"}}`...)
+	res, err := s.server.Advance(payload, deposit)
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Empty(res.Notices)
+	s.Require().Len(res.Reports, 1)
+	var report rollups.ErrorReport
+	s.NoError(json.Unmarshal(res.Reports[0], &report))
+	s.Equal([]rollups.FieldError{{Field: "description", Tag: "required"}}, report.Fields)
+
+	res, err = s.server.Inspect([]byte("wallet/ether/" + sender.Hex()))
+	s.Require().NoError(err)
+	s.Require().Len(res.Reports, 1)
+	var balance usecase.FindBalanceOutputDTO
+	s.NoError(json.Unmarshal(res.Reports[0], &balance))
+	s.Equal(big.NewInt(1000), balance.Balance)
+}
+
+func (s *ToDoApplicationSuite) TestUnsupportedDepositIsAccepted() {
+	token := common.HexToAddress("0xFBdB734EF6a23aD76863CbA6f10d0C5CBBD8342C")
+	deposit := metadata
+	deposit.MsgSender = rollups.DefaultPortalConfig().ERC1155SinglePortal
+	bytesType, err := abi.NewType("bytes", "", nil)
+	s.Require().NoError(err)
+	// abi.encode(baseLayerData, execLayerData)
+	layerData, err := abi.Arguments{{Type: bytesType}, {Type: bytesType}}.Pack([]byte{}, []byte{})
+	s.Require().NoError(err)
+	payload := append(token.Bytes(), metadata.MsgSender.Bytes()...)
+	payload = append(payload, common.LeftPadBytes(big.NewInt(1).Bytes(), 32)...)
+	payload = append(payload, common.LeftPadBytes(big.NewInt(50).Bytes(), 32)...)
+	payload = append(payload, layerData...)
+
+	res, err := s.server.Advance(payload, deposit)
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Require().Len(res.Reports, 1)
+	var report rollups.ErrorReport
+	s.NoError(json.Unmarshal(res.Reports[0], &report))
+	s.Contains(report.Error, "unsupported asset")
+}
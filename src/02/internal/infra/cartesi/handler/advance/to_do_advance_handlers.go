@@ -1,10 +1,11 @@
 package advance
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"math/big"
 
-	"github.com/go-playground/validator/v10"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
 	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
 	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/usecase"
 	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
@@ -20,80 +21,129 @@ func NewToDoAdvanceHandlers(toDoRepository repository.ToDoRepository) *ToDoAdvan
 	}
 }
 
-func (h *ToDoAdvanceHandlers) CreateToDoHandler(payload []byte, metadata rollups.Metadata) error {
-	var input usecase.CreateToDoInputDTO
-	if err := json.Unmarshal(payload, &input); err != nil {
-		return err
-	}
+func (h *ToDoAdvanceHandlers) CreateToDoHandler(ctx context.Context, input usecase.CreateToDoInputDTO, metadata rollups.Metadata) (*usecase.CreateToDoOutputDTO, error) {
+	createToDo := usecase.NewCreateToDoUseCase(h.ToDoRepository)
+	return createToDo.Execute(&input, metadata)
+}
 
-	validator := validator.New()
-	if err := validator.Struct(input); err != nil {
-		return fmt.Errorf("failed to validate input: %w", err)
-	}
+func (h *ToDoAdvanceHandlers) UpdateToDoHandler(ctx context.Context, input usecase.UpdateToDoInputDTO, metadata rollups.Metadata) (*usecase.UpdateToDoOutputDTO, error) {
+	updateToDo := usecase.NewUpdateToDoUseCase(h.ToDoRepository)
+	return updateToDo.Execute(&input, metadata)
+}
 
-	createToDo := usecase.NewCreateToDoUseCase(h.ToDoRepository)
-	res, err := createToDo.Execute(&input, metadata)
-	if err != nil {
-		return err
-	}
-	toDo, err := json.Marshal(res)
+// RestoreToDoHandler brings back a deleted todo. It is an advance route
+// rather than an inspect one because inspect requests cannot change state.
+func (h *ToDoAdvanceHandlers) RestoreToDoHandler(ctx context.Context, input usecase.RestoreToDoInputDTO, metadata rollups.Metadata) (*usecase.RestoreToDoOutputDTO, error) {
+	restoreToDo := usecase.NewRestoreToDoUseCase(h.ToDoRepository)
+	return restoreToDo.Execute(&input, metadata)
+}
+
+// CompleteToDoHandler marks a todo as completed, leaving its other fields
+// as they are.
+func (h *ToDoAdvanceHandlers) CompleteToDoHandler(ctx context.Context, input usecase.ToggleToDoInputDTO, metadata rollups.Metadata) (*usecase.UpdateToDoOutputDTO, error) {
+	return h.UpdateToDoHandler(ctx, usecase.UpdateToDoInputDTO{Id: input.Id, Completed: ptr(true)}, metadata)
+}
+
+// ReopenToDoHandler marks a completed todo as pending again.
+func (h *ToDoAdvanceHandlers) ReopenToDoHandler(ctx context.Context, input usecase.ToggleToDoInputDTO, metadata rollups.Metadata) (*usecase.UpdateToDoOutputDTO, error) {
+	return h.UpdateToDoHandler(ctx, usecase.UpdateToDoInputDTO{Id: input.Id, Completed: ptr(false)}, metadata)
+}
+
+func (h *ToDoAdvanceHandlers) DeleteToDoHandler(ctx context.Context, input usecase.DeleteToDoInputDTO, metadata rollups.Metadata) (*usecase.DeleteToDoOutputDTO, error) {
+	deleteToDo := usecase.NewDeleteToDoUseCase(h.ToDoRepository)
+	return deleteToDo.Execute(&input, metadata)
+}
+
+// CreateToDoABIHandler serves createToDo(string,string) calldata.
+func (h *ToDoAdvanceHandlers) CreateToDoABIHandler(ctx context.Context, args []any, metadata rollups.Metadata) error {
+	res, err := h.CreateToDoHandler(ctx, usecase.CreateToDoInputDTO{
+		Title:       args[0].(string),
+		Description: args[1].(string),
+	}, metadata)
 	if err != nil {
 		return err
 	}
-	rollups.SendNotice(&rollups.NoticeRequest{
-		Payload: rollups.Str2Hex(fmt.Sprintf("todo created - %s", toDo)),
-	})
-	return nil
+	_, err = rollups.SendMarshaledNotice(ctx, res)
+	return err
 }
 
-func (h *ToDoAdvanceHandlers) UpdateToDoHandler(payload []byte, metadata rollups.Metadata) error {
-	var input usecase.UpdateToDoInputDTO
-	if err := json.Unmarshal(payload, &input); err != nil {
+// UpdateToDoABIHandler serves updateToDo(uint256,string,string,bool) calldata.
+func (h *ToDoAdvanceHandlers) UpdateToDoABIHandler(ctx context.Context, args []any, metadata rollups.Metadata) error {
+	id, err := toDoId(args[0].(*big.Int))
+	if err != nil {
 		return err
 	}
-
-	validator := validator.New()
-	if err := validator.Struct(input); err != nil {
-		return fmt.Errorf("failed to validate input: %w", err)
+	res, err := h.UpdateToDoHandler(ctx, usecase.UpdateToDoInputDTO{
+		Id:          id,
+		Title:       ptr(args[1].(string)),
+		Description: ptr(args[2].(string)),
+		Completed:   ptr(args[3].(bool)),
+	}, metadata)
+	if err != nil {
+		return err
 	}
+	_, err = rollups.SendMarshaledNotice(ctx, res)
+	return err
+}
 
-	updateToDo := usecase.NewUpdateToDoUseCase(h.ToDoRepository)
-	res, err := updateToDo.Execute(&input, metadata)
+// RestoreToDoABIHandler serves restoreToDo(uint256) calldata.
+func (h *ToDoAdvanceHandlers) RestoreToDoABIHandler(ctx context.Context, args []any, metadata rollups.Metadata) error {
+	id, err := toDoId(args[0].(*big.Int))
 	if err != nil {
 		return err
 	}
-	toDo, err := json.Marshal(res)
+	res, err := h.RestoreToDoHandler(ctx, usecase.RestoreToDoInputDTO{Id: id}, metadata)
 	if err != nil {
 		return err
 	}
-	rollups.SendNotice(&rollups.NoticeRequest{
-		Payload: rollups.Str2Hex(fmt.Sprintf("todo updated - %s", toDo)),
-	})
-	return nil
+	_, err = rollups.SendMarshaledNotice(ctx, res)
+	return err
 }
 
-func (h *ToDoAdvanceHandlers) DeleteToDoHandler(payload []byte, metadata rollups.Metadata) error {
-	var input usecase.DeleteToDoInputDTO
-	if err := json.Unmarshal(payload, &input); err != nil {
+// CompleteToDoABIHandler serves completeToDo(uint256) calldata.
+func (h *ToDoAdvanceHandlers) CompleteToDoABIHandler(ctx context.Context, args []any, metadata rollups.Metadata) error {
+	return h.toggleToDoABI(ctx, args, metadata, h.CompleteToDoHandler)
+}
+
+// ReopenToDoABIHandler serves reopenToDo(uint256) calldata.
+func (h *ToDoAdvanceHandlers) ReopenToDoABIHandler(ctx context.Context, args []any, metadata rollups.Metadata) error {
+	return h.toggleToDoABI(ctx, args, metadata, h.ReopenToDoHandler)
+}
+
+func (h *ToDoAdvanceHandlers) toggleToDoABI(ctx context.Context, args []any, metadata rollups.Metadata, handler rollups.JSONHandlerFunc[usecase.ToggleToDoInputDTO, *usecase.UpdateToDoOutputDTO]) error {
+	id, err := toDoId(args[0].(*big.Int))
+	if err != nil {
 		return err
 	}
-
-	validator := validator.New()
-	if err := validator.Struct(input); err != nil {
-		return fmt.Errorf("failed to validate input: %w", err)
+	res, err := handler(ctx, usecase.ToggleToDoInputDTO{Id: id}, metadata)
+	if err != nil {
+		return err
 	}
+	_, err = rollups.SendMarshaledNotice(ctx, res)
+	return err
+}
 
-	deleteToDo := usecase.NewDeleteToDoUseCase(h.ToDoRepository)
-	err := deleteToDo.Execute(&input)
+// DeleteToDoABIHandler serves deleteToDo(uint256) calldata.
+func (h *ToDoAdvanceHandlers) DeleteToDoABIHandler(ctx context.Context, args []any, metadata rollups.Metadata) error {
+	id, err := toDoId(args[0].(*big.Int))
 	if err != nil {
 		return err
 	}
-	toDo, err := json.Marshal(input)
+	res, err := h.DeleteToDoHandler(ctx, usecase.DeleteToDoInputDTO{Id: id}, metadata)
 	if err != nil {
 		return err
 	}
-	rollups.SendNotice(&rollups.NoticeRequest{
-		Payload: rollups.Str2Hex(fmt.Sprintf("todo deleted - %s", toDo)),
-	})
-	return nil
+	_, err = rollups.SendMarshaledNotice(ctx, res)
+	return err
+}
+
+func toDoId(id *big.Int) (uint, error) {
+	if !id.IsUint64() || id.Uint64() > uint64(^uint(0)) {
+		return 0, fmt.Errorf("%w: todo id out of range: %s", domain.ErrNotFound, id)
+	}
+	return uint(id.Uint64()), nil
+}
+
+func ptr[T any](v T) *T {
+	return &v
 }
@@ -0,0 +1,63 @@
+package advance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/usecase"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/wallet"
+)
+
+type WalletAdvanceHandlers struct {
+	WalletRepository repository.WalletRepository
+}
+
+func NewWalletAdvanceHandlers(walletRepository repository.WalletRepository) *WalletAdvanceHandlers {
+	return &WalletAdvanceHandlers{
+		WalletRepository: walletRepository,
+	}
+}
+
+func (h *WalletAdvanceHandlers) DepositHandler(ctx context.Context, deposit rollups.Deposit, metadata rollups.Metadata) error {
+	depositAsset := usecase.NewDepositAssetUseCase(h.WalletRepository)
+	err := depositAsset.Execute(deposit)
+	if errors.Is(err, wallet.ErrUnsupportedAsset) {
+		// Rejecting the input would not release the tokens from the portal,
+		// so accept it and tell the depositor the asset is not credited.
+		return rollups.ReportError(ctx, err)
+	}
+	return fatalIfCorrupted(err)
+}
+
+func (h *WalletAdvanceHandlers) TransferHandler(ctx context.Context, input usecase.TransferAssetInputDTO, metadata rollups.Metadata) (*usecase.TransferAssetOutputDTO, error) {
+	transferAsset := usecase.NewTransferAssetUseCase(h.WalletRepository)
+	res, err := transferAsset.Execute(&input, metadata)
+	if err != nil {
+		return nil, fatalIfCorrupted(err)
+	}
+	return res, nil
+}
+
+func (h *WalletAdvanceHandlers) WithdrawHandler(ctx context.Context, input usecase.WithdrawAssetInputDTO, metadata rollups.Metadata) (*usecase.WithdrawAssetOutputDTO, error) {
+	withdrawAsset := usecase.NewWithdrawAssetUseCase(h.WalletRepository)
+	res, voucher, err := withdrawAsset.Execute(&input, metadata)
+	if err != nil {
+		return nil, fatalIfCorrupted(err)
+	}
+	if _, err := rollups.ClientFromContext(ctx).SendVoucher(ctx, voucher); err != nil {
+		return nil, fmt.Errorf("failed to send voucher: %w", err)
+	}
+	return res, nil
+}
+
+// fatalIfCorrupted halts the application when the ledger can no longer be
+// trusted, instead of rejecting the input and carrying on.
+func fatalIfCorrupted(err error) error {
+	if errors.Is(err, wallet.ErrCorruptedState) {
+		return rollups.Fatal(err)
+	}
+	return err
+}
@@ -1,8 +1,13 @@
 package inspect
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
 
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
 	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
 	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/usecase"
 	rollups "github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
@@ -10,26 +15,120 @@ import (
 
 type ToDoInspectHandlers struct {
 	ToDoRepository repository.ToDoRepository
+	Client         *rollups.Client
 }
 
-func NewToDoInspectHandlers(toDoRepository repository.ToDoRepository) *ToDoInspectHandlers {
+func NewToDoInspectHandlers(toDoRepository repository.ToDoRepository, client *rollups.Client) *ToDoInspectHandlers {
 	return &ToDoInspectHandlers{
 		ToDoRepository: toDoRepository,
+		Client:         client,
 	}
 }
 
-func (h *ToDoInspectHandlers) FindAllToDosHandler() error {
+func (h *ToDoInspectHandlers) FindAllToDosHandler(ctx context.Context, payload []byte) error {
 	findAllToDos := usecase.NewFindAllToDosUseCase(h.ToDoRepository)
 	res, err := findAllToDos.Execute()
 	if err != nil {
 		return err
 	}
-	toDos, err := json.Marshal(res)
+	return h.report(ctx, res)
+}
+
+// FindToDoByIdHandler serves todos/{id}. A malformed or unknown id is
+// answered with a rollups.ErrorReport.
+func (h *ToDoInspectHandlers) FindToDoByIdHandler(ctx context.Context, payload []byte) error {
+	id, err := strconv.ParseUint(rollups.PathParam(ctx, "id"), 10, 0)
+	if err != nil || id == 0 {
+		return reportError(ctx, fmt.Errorf("%w: invalid todo id %q", domain.ErrNotFound, rollups.PathParam(ctx, "id")))
+	}
+	findToDoById := usecase.NewFindToDoByIdUseCase(h.ToDoRepository)
+	res, err := findToDoById.Execute(&usecase.FindToDoByIdInputDTO{Id: uint(id)})
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return reportError(ctx, err)
+		}
+		return err
+	}
+	return h.report(ctx, res)
+}
+
+// FindToDoHistoryHandler serves todos/{id}/history, the changes made to a
+// todo, deleted or not, oldest first.
+func (h *ToDoInspectHandlers) FindToDoHistoryHandler(ctx context.Context, payload []byte) error {
+	id, err := strconv.ParseUint(rollups.PathParam(ctx, "id"), 10, 0)
+	if err != nil || id == 0 {
+		return reportError(ctx, fmt.Errorf("%w: invalid todo id %q", domain.ErrNotFound, rollups.PathParam(ctx, "id")))
+	}
+	findToDoHistory := usecase.NewFindToDoHistoryUseCase(h.ToDoRepository)
+	res, err := findToDoHistory.Execute(&usecase.FindToDoHistoryInputDTO{Id: uint(id)})
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return reportError(ctx, err)
+		}
+		return err
+	}
+	return h.report(ctx, res)
+}
+
+// FindToDosHandler serves todos/query. The inspect payload, if any, is a
+// usecase.FindToDosInputDTO.
+func (h *ToDoInspectHandlers) FindToDosHandler(ctx context.Context, payload []byte) error {
+	var input usecase.FindToDosInputDTO
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &input); err != nil {
+			return reportError(ctx, fmt.Errorf("%w: %w", rollups.ErrInvalidPayload, err))
+		}
+	}
+	findToDos := usecase.NewFindToDosUseCase(h.ToDoRepository)
+	res, err := findToDos.Execute(&input)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidQuery) {
+			return reportError(ctx, err)
+		}
+		return err
+	}
+	return h.report(ctx, res)
+}
+
+// FindToDosByOwnerHandler serves todos/owner/{owner}.
+func (h *ToDoInspectHandlers) FindToDosByOwnerHandler(ctx context.Context, payload []byte) error {
+	owner, err := addressParam(ctx, "owner")
+	if err != nil {
+		return reportError(ctx, err)
+	}
+	findToDosByOwner := usecase.NewFindToDosByOwnerUseCase(h.ToDoRepository)
+	res, err := findToDosByOwner.Execute(&usecase.FindToDosByOwnerInputDTO{Owner: owner})
+	if err != nil {
+		return err
+	}
+	return h.report(ctx, res)
+}
+
+func (h *ToDoInspectHandlers) FindToDoStatsHandler(ctx context.Context, payload []byte) error {
+	findToDoStats := usecase.NewFindToDoStatsUseCase(h.ToDoRepository)
+	res, err := findToDoStats.Execute()
 	if err != nil {
 		return err
 	}
-	rollups.SendReport(&rollups.ReportRequest{
-		Payload: rollups.Str2Hex(string(toDos)),
-	})
+	return h.report(ctx, res)
+}
+
+func (h *ToDoInspectHandlers) report(ctx context.Context, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := h.Client.Report(ctx, body); err != nil {
+		return fmt.Errorf("failed to send report: %w", err)
+	}
 	return nil
 }
+
+// reportError answers the inspect request with cause as a
+// rollups.ErrorReport, so clients can tell why it was rejected.
+func reportError(ctx context.Context, cause error) error {
+	if err := rollups.ReportError(ctx, cause); err != nil {
+		return errors.Join(cause, err)
+	}
+	return cause
+}
@@ -0,0 +1,84 @@
+package inspect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/usecase"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+)
+
+type WalletInspectHandlers struct {
+	WalletRepository repository.WalletRepository
+	Client           *rollups.Client
+}
+
+func NewWalletInspectHandlers(walletRepository repository.WalletRepository, client *rollups.Client) *WalletInspectHandlers {
+	return &WalletInspectHandlers{
+		WalletRepository: walletRepository,
+		Client:           client,
+	}
+}
+
+// EtherBalanceHandler serves wallet/ether/{owner}.
+func (h *WalletInspectHandlers) EtherBalanceHandler(ctx context.Context, payload []byte) error {
+	owner, err := addressParam(ctx, "owner")
+	if err != nil {
+		return reportError(ctx, err)
+	}
+	return h.findBalance(ctx, &usecase.FindBalanceInputDTO{Asset: usecase.AssetEther, Owner: owner})
+}
+
+// ERC20BalanceHandler serves wallet/erc20/{token}/{owner}.
+func (h *WalletInspectHandlers) ERC20BalanceHandler(ctx context.Context, payload []byte) error {
+	token, err := addressParam(ctx, "token")
+	if err != nil {
+		return reportError(ctx, err)
+	}
+	owner, err := addressParam(ctx, "owner")
+	if err != nil {
+		return reportError(ctx, err)
+	}
+	return h.findBalance(ctx, &usecase.FindBalanceInputDTO{Asset: usecase.AssetERC20, Token: token, Owner: owner})
+}
+
+// ERC721OwnerHandler serves wallet/erc721/{token}/{token_id}.
+func (h *WalletInspectHandlers) ERC721OwnerHandler(ctx context.Context, payload []byte) error {
+	token, err := addressParam(ctx, "token")
+	if err != nil {
+		return reportError(ctx, err)
+	}
+	tokenId, ok := new(big.Int).SetString(rollups.PathParam(ctx, "token_id"), 0)
+	if !ok {
+		return reportError(ctx, fmt.Errorf("invalid token id: %q", rollups.PathParam(ctx, "token_id")))
+	}
+	return h.findBalance(ctx, &usecase.FindBalanceInputDTO{Asset: usecase.AssetERC721, Token: token, TokenId: tokenId})
+}
+
+func (h *WalletInspectHandlers) findBalance(ctx context.Context, input *usecase.FindBalanceInputDTO) error {
+	findBalance := usecase.NewFindBalanceUseCase(h.WalletRepository)
+	res, err := findBalance.Execute(input)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	if err := h.Client.Report(ctx, body); err != nil {
+		return fmt.Errorf("failed to send report: %w", err)
+	}
+	return nil
+}
+
+func addressParam(ctx context.Context, name string) (common.Address, error) {
+	value := rollups.PathParam(ctx, name)
+	if !common.IsHexAddress(value) {
+		return common.Address{}, fmt.Errorf("invalid %s address: %q", name, value)
+	}
+	return common.HexToAddress(value), nil
+}
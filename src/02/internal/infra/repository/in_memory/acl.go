@@ -0,0 +1,22 @@
+package in_memory
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/acl"
+)
+
+func (r *InMemoryRepository) HasRole(account common.Address, role acl.Role) (bool, error) {
+	return r.Roles.HasRole(account, role)
+}
+
+func (r *InMemoryRepository) GrantRole(account common.Address, role acl.Role) error {
+	return r.Roles.GrantRole(account, role)
+}
+
+func (r *InMemoryRepository) RevokeRole(account common.Address, role acl.Role) error {
+	return r.Roles.RevokeRole(account, role)
+}
+
+func (r *InMemoryRepository) FindRoleAssignments() ([]*acl.Assignment, error) {
+	return r.Roles.FindRoleAssignments()
+}
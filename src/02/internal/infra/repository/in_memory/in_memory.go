@@ -3,27 +3,41 @@ package in_memory
 import (
 	"sync"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/acl"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/wallet"
 )
 
 type InMemoryRepository struct {
-	Db     map[uint]*domain.ToDo
-	Mutex  *sync.RWMutex
-	NextID uint
+	*wallet.MemoryStore
+	Db      map[uint]*domain.ToDo
+	Changes []*domain.ToDoChange
+	Mutex   *sync.RWMutex
+	NextID  uint
+	Nonces  map[common.Address]uint64
+	Roles   *acl.MemoryStore
 }
 
 func (r *InMemoryRepository) Close() error {
 	r.Mutex.Lock()
 	defer r.Mutex.Unlock()
 	r.Db = make(map[uint]*domain.ToDo)
+	r.Changes = nil
 	r.NextID = 1
+	r.Nonces = make(map[common.Address]uint64)
+	r.MemoryStore = wallet.NewMemoryStore()
+	r.Roles = acl.NewMemoryStore()
 	return nil
 }
 
 func NewInMemoryRepository() (*InMemoryRepository, error) {
 	return &InMemoryRepository{
-		Db:     make(map[uint]*domain.ToDo),
-		Mutex:  &sync.RWMutex{},
-		NextID: 1,
+		MemoryStore: wallet.NewMemoryStore(),
+		Db:          make(map[uint]*domain.ToDo),
+		Mutex:       &sync.RWMutex{},
+		NextID:      1,
+		Nonces:      make(map[common.Address]uint64),
+		Roles:       acl.NewMemoryStore(),
 	}, nil
 }
@@ -0,0 +1,16 @@
+package in_memory
+
+import "github.com/ethereum/go-ethereum/common"
+
+func (r *InMemoryRepository) FindNonce(signer common.Address) (uint64, error) {
+	r.Mutex.RLock()
+	defer r.Mutex.RUnlock()
+	return r.Nonces[signer], nil
+}
+
+func (r *InMemoryRepository) UpdateNonce(signer common.Address, nonce uint64) error {
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+	r.Nonces[signer] = nonce
+	return nil
+}
@@ -1,6 +1,8 @@
 package in_memory
 
 import (
+	"sort"
+
 	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
 )
 
@@ -10,7 +12,8 @@ func (r *InMemoryRepository) CreateToDo(input *domain.ToDo) (*domain.ToDo, error
 
 	input.Id = r.NextID
 	r.NextID++
-	r.Db[input.Id] = input
+	stored := *input
+	r.Db[input.Id] = &stored
 	return input, nil
 }
 
@@ -20,37 +23,94 @@ func (r *InMemoryRepository) FindAllToDos() ([]*domain.ToDo, error) {
 
 	var todos []*domain.ToDo
 	for _, todo := range r.Db {
-		todos = append(todos, todo)
+		if !todo.IsDeleted() {
+			res := *todo
+			todos = append(todos, &res)
+		}
 	}
+	sort.Slice(todos, func(i, j int) bool { return todos[i].Id < todos[j].Id })
 	return todos, nil
 }
 
-func (r *InMemoryRepository) UpdateToDo(input *domain.ToDo) (*domain.ToDo, error) {
-	r.Mutex.Lock()
-	defer r.Mutex.Unlock()
+func (r *InMemoryRepository) FindToDos(query *domain.ToDoQuery) (*domain.ToDoPage, error) {
+	r.Mutex.RLock()
+	defer r.Mutex.RUnlock()
 
-	todo, exists := r.Db[input.Id]
-	if !exists {
-		return nil, domain.ErrNotFound
+	todos := []*domain.ToDo{}
+	for _, todo := range r.Db {
+		if query.Match(todo) {
+			todos = append(todos, todo)
+		}
 	}
+	sort.Slice(todos, func(i, j int) bool { return query.Less(todos[i], todos[j]) })
 
-	todo.Title = input.Title
-	todo.Description = input.Description
-	todo.Completed = input.Completed
+	page := &domain.ToDoPage{Total: len(todos)}
+	start := min(query.Offset, len(todos))
+	end := len(todos)
+	if query.Limit > 0 {
+		end = min(start+query.Limit, end)
+	}
+	// Hand out copies, so that callers can only change todos through
+	// UpdateToDo.
+	page.ToDos = make([]*domain.ToDo, 0, end-start)
+	for _, todo := range todos[start:end] {
+		res := *todo
+		page.ToDos = append(page.ToDos, &res)
+	}
+	return page, nil
+}
 
-	r.Db[input.Id] = todo
+func (r *InMemoryRepository) FindToDoById(id uint) (*domain.ToDo, error) {
+	r.Mutex.RLock()
+	defer r.Mutex.RUnlock()
 
-	return todo, nil
+	todo, exists := r.Db[id]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	res := *todo
+	return &res, nil
 }
 
-func (r *InMemoryRepository) DeleteToDo(id uint) error {
+func (r *InMemoryRepository) UpdateToDo(id uint, update func(toDo *domain.ToDo) error) (*domain.ToDo, error) {
 	r.Mutex.Lock()
 	defer r.Mutex.Unlock()
-	_, exists := r.Db[id]
+
+	stored, exists := r.Db[id]
 	if !exists {
-		return domain.ErrNotFound
+		return nil, domain.ErrNotFound
+	}
+	// update may fail after changing some fields, so it works on a copy.
+	todo := *stored
+	if err := update(&todo); err != nil {
+		return nil, err
 	}
+	r.Db[id] = &todo
 
-	delete(r.Db, id)
+	res := todo
+	return &res, nil
+}
+
+func (r *InMemoryRepository) AppendToDoChange(change *domain.ToDoChange) error {
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+
+	change.Id = uint(len(r.Changes) + 1)
+	stored := *change
+	r.Changes = append(r.Changes, &stored)
 	return nil
 }
+
+func (r *InMemoryRepository) FindToDoChanges(toDoId uint) ([]*domain.ToDoChange, error) {
+	r.Mutex.RLock()
+	defer r.Mutex.RUnlock()
+
+	changes := []*domain.ToDoChange{}
+	for _, change := range r.Changes {
+		if change.ToDoId == toDoId {
+			res := *change
+			changes = append(changes, &res)
+		}
+	}
+	return changes, nil
+}
@@ -1,15 +1,54 @@
 package repository
 
-import "github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
+import (
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/acl"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/wallet"
+)
 
+// ToDoRepository keeps todos and their history. Deleted todos stay stored
+// with DeletedAt set; only FindToDoById and queries asking for them return
+// them.
 type ToDoRepository interface {
 	CreateToDo(toDo *domain.ToDo) (*domain.ToDo, error)
+	// FindAllToDos returns copies of the todos that are not deleted.
 	FindAllToDos() ([]*domain.ToDo, error)
-	UpdateToDo(toDo *domain.ToDo) (*domain.ToDo, error)
-	DeleteToDo(id uint) error
+	// FindToDos returns the page of todos selected by query, ordered as it
+	// asks. The todos are copies; change them through UpdateToDo.
+	FindToDos(query *domain.ToDoQuery) (*domain.ToDoPage, error)
+	// FindToDoById returns a copy of the stored todo, deleted or not, or an
+	// error wrapping domain.ErrNotFound.
+	FindToDoById(id uint) (*domain.ToDo, error)
+	// UpdateToDo loads the todo with the given id, lets update change it and
+	// stores every field of the result. An error from update leaves the
+	// stored todo untouched; a missing todo is reported as domain.ErrNotFound.
+	UpdateToDo(id uint, update func(toDo *domain.ToDo) error) (*domain.ToDo, error)
+	// AppendToDoChange adds change to the history of its todo. Entries are
+	// never updated or removed.
+	AppendToDoChange(change *domain.ToDoChange) error
+	// FindToDoChanges returns the history of a todo, oldest first.
+	FindToDoChanges(toDoId uint) ([]*domain.ToDoChange, error)
+}
+
+type WalletRepository interface {
+	wallet.Store
+}
+
+// NonceRepository keeps the nonces of signed inputs.
+type NonceRepository interface {
+	rollups.NonceStore
+}
+
+// ACLRepository keeps the role assignments of the acl package.
+type ACLRepository interface {
+	acl.Store
 }
 
 type Repository interface {
 	ToDoRepository
+	WalletRepository
+	NonceRepository
+	ACLRepository
 	Close() error
 }
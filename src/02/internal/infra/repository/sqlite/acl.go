@@ -0,0 +1,51 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/acl"
+	"gorm.io/gorm/clause"
+)
+
+type roleAssignment struct {
+	Account string `gorm:"primaryKey"`
+	Role    string `gorm:"primaryKey"`
+}
+
+func (r *SQLiteRepository) HasRole(account common.Address, role acl.Role) (bool, error) {
+	var count int64
+	err := r.Db.Model(&roleAssignment{}).Where("account = ? AND role = ?", account.Hex(), string(role)).Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to find role: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *SQLiteRepository) GrantRole(account common.Address, role acl.Role) error {
+	row := roleAssignment{Account: account.Hex(), Role: string(role)}
+	if err := r.Db.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+		return fmt.Errorf("failed to grant role: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) RevokeRole(account common.Address, role acl.Role) error {
+	if err := r.Db.Delete(&roleAssignment{Account: account.Hex(), Role: string(role)}).Error; err != nil {
+		return fmt.Errorf("failed to revoke role: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) FindRoleAssignments() ([]*acl.Assignment, error) {
+	var rows []roleAssignment
+	if err := r.Db.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to find role assignments: %w", err)
+	}
+	assignments := make([]*acl.Assignment, 0, len(rows))
+	for _, row := range rows {
+		assignments = append(assignments, &acl.Assignment{Account: common.HexToAddress(row.Account), Role: acl.Role(row.Role)})
+	}
+	acl.SortAssignments(assignments)
+	return assignments, nil
+}
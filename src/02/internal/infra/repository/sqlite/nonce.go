@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type signerNonce struct {
+	Signer string `gorm:"primaryKey"`
+	Nonce  uint64 `gorm:"not null"`
+}
+
+func (r *SQLiteRepository) FindNonce(signer common.Address) (uint64, error) {
+	var nonce signerNonce
+	err := r.Db.Where("signer = ?", signer.Hex()).Take(&nonce).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to find nonce: %w", err)
+	}
+	return nonce.Nonce, nil
+}
+
+func (r *SQLiteRepository) UpdateNonce(signer common.Address, nonce uint64) error {
+	row := signerNonce{Signer: signer.Hex(), Nonce: nonce}
+	if err := r.Db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&row).Error; err != nil {
+		return fmt.Errorf("failed to update nonce: %w", err)
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -48,6 +49,10 @@ func NewSQLiteRepository(ctx context.Context, conn string) (*SQLiteRepository, e
 
 	db = db.WithContext(ctx)
 
+	if err := db.AutoMigrate(&domain.ToDo{}, &domain.ToDoChange{}, &etherBalance{}, &erc20Balance{}, &erc721Owner{}, &signerNonce{}, &roleAssignment{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
 	return &SQLiteRepository{
 		Db: db,
 	}, nil
@@ -16,7 +16,7 @@ func (r *SQLiteRepository) CreateToDo(input *domain.ToDo) (*domain.ToDo, error)
 
 func (r *SQLiteRepository) FindAllToDos() ([]*domain.ToDo, error) {
 	var toDos []*domain.ToDo
-	if err := r.Db.Find(&toDos).Error; err != nil {
+	if err := r.Db.Where("deleted_at = 0").Order("id").Find(&toDos).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("failed to find all todos: %w", domain.ErrNotFound)
 		}
@@ -25,28 +25,59 @@ func (r *SQLiteRepository) FindAllToDos() ([]*domain.ToDo, error) {
 	return toDos, nil
 }
 
-func (r *SQLiteRepository) UpdateToDo(input *domain.ToDo) (*domain.ToDo, error) {
-	if err := r.Db.Updates(input).Error; err != nil {
-		return nil, fmt.Errorf("failed to update todo: %w", err)
+func (r *SQLiteRepository) FindToDos(query *domain.ToDoQuery) (*domain.ToDoPage, error) {
+	db := r.Db.Model(&domain.ToDo{})
+	if query.Deleted {
+		db = db.Where("deleted_at <> 0")
+	} else {
+		db = db.Where("deleted_at = 0")
 	}
-	toDo, err := r.findToDoById(input.Id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to update todo: %w", err)
+	if query.Completed != nil {
+		db = db.Where("completed = ?", *query.Completed)
+	}
+	if query.Owner != nil {
+		db = db.Where("owner = ?", *query.Owner)
+	}
+	if query.CreatedFrom != 0 {
+		db = db.Where("created_at >= ?", query.CreatedFrom)
+	}
+	if query.CreatedTo != 0 {
+		db = db.Where("created_at <= ?", query.CreatedTo)
+	}
+	if query.UpdatedFrom != 0 {
+		db = db.Where("updated_at >= ?", query.UpdatedFrom)
+	}
+	if query.UpdatedTo != 0 {
+		db = db.Where("updated_at <= ?", query.UpdatedTo)
 	}
-	return toDo, nil
-}
 
-func (r *SQLiteRepository) DeleteToDo(id uint) error {
-	if err := r.Db.Delete(&domain.ToDo{}, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return fmt.Errorf("failed to delete todo: %w", domain.ErrNotFound)
-		}
-		return fmt.Errorf("failed to delete todo: %w", err)
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count todos: %w", err)
 	}
-	return nil
+
+	direction := "ASC"
+	if query.Descending {
+		direction = "DESC"
+	}
+	if query.SortBy != "" && query.SortBy != domain.SortToDosById {
+		// SortBy is one of the domain.ToDoSort columns, checked by
+		// ToDoQuery.Validate, so it is safe to splice in.
+		db = db.Order(fmt.Sprintf("%s %s", query.SortBy, direction))
+	}
+	db = db.Order("id " + direction).Offset(query.Offset)
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+
+	toDos := []*domain.ToDo{}
+	if err := db.Find(&toDos).Error; err != nil {
+		return nil, fmt.Errorf("failed to find todos: %w", err)
+	}
+	return &domain.ToDoPage{ToDos: toDos, Total: int(total)}, nil
 }
 
-func (r *SQLiteRepository) findToDoById(id uint) (*domain.ToDo, error) {
+func (r *SQLiteRepository) FindToDoById(id uint) (*domain.ToDo, error) {
 	var toDo domain.ToDo
 	if err := r.Db.First(&toDo, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -56,3 +87,33 @@ func (r *SQLiteRepository) findToDoById(id uint) (*domain.ToDo, error) {
 	}
 	return &toDo, nil
 }
+
+func (r *SQLiteRepository) UpdateToDo(id uint, update func(toDo *domain.ToDo) error) (*domain.ToDo, error) {
+	toDo, err := r.FindToDoById(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update todo: %w", err)
+	}
+	if err := update(toDo); err != nil {
+		return nil, err
+	}
+	// Select("*") writes zero values too, e.g. completed back to false.
+	if err := r.Db.Model(toDo).Select("*").Updates(toDo).Error; err != nil {
+		return nil, fmt.Errorf("failed to update todo: %w", err)
+	}
+	return toDo, nil
+}
+
+func (r *SQLiteRepository) AppendToDoChange(change *domain.ToDoChange) error {
+	if err := r.Db.Create(change).Error; err != nil {
+		return fmt.Errorf("failed to append todo change: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) FindToDoChanges(toDoId uint) ([]*domain.ToDoChange, error) {
+	changes := []*domain.ToDoChange{}
+	if err := r.Db.Where("to_do_id = ?", toDoId).Order("id").Find(&changes).Error; err != nil {
+		return nil, fmt.Errorf("failed to find todo changes: %w", err)
+	}
+	return changes, nil
+}
@@ -0,0 +1,176 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository/in_memory"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/usecase"
+	"github.com/stretchr/testify/suite"
+)
+
+var owners = []common.Address{
+	common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"),
+	common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"),
+	common.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906"),
+}
+
+func TestFindToDosSuite(t *testing.T) {
+	suite.Run(t, new(FindToDosSuite))
+}
+
+// FindToDosSuite stores the same todos in sqlite and in memory and checks
+// that both backends answer every query alike.
+type FindToDosSuite struct {
+	suite.Suite
+	sqlite *SQLiteRepository
+	memory *in_memory.InMemoryRepository
+}
+
+func (s *FindToDosSuite) SetupTest() {
+	var err error
+	s.sqlite, err = NewSQLiteRepository(context.Background(), "sqlite://"+filepath.Join(s.T().TempDir(), "todo.db"))
+	s.Require().NoError(err)
+	s.memory, err = in_memory.NewInMemoryRepository()
+	s.Require().NoError(err)
+
+	// Timestamps repeat so that sorting has ties to break by id.
+	for i := uint64(1); i <= 120; i++ {
+		toDo := domain.ToDo{
+			Owner:       owners[i%3],
+			Title:       "title",
+			Description: "description",
+			Completed:   i%4 == 0,
+			CreatedAt:   1000 + i*37%50,
+		}
+		if i%5 != 0 {
+			toDo.UpdatedAt = 2000 + i*13%40
+		}
+		if i%10 == 0 {
+			toDo.DeletedAt = 3000
+		}
+		stored := toDo
+		_, err := s.sqlite.CreateToDo(&stored)
+		s.Require().NoError(err)
+		stored = toDo
+		_, err = s.memory.CreateToDo(&stored)
+		s.Require().NoError(err)
+	}
+}
+
+func (s *FindToDosSuite) TearDownTest() {
+	s.NoError(s.sqlite.Close())
+	s.NoError(s.memory.Close())
+}
+
+// find runs query on both backends and returns the sqlite page after
+// checking that in-memory returned the same one.
+func (s *FindToDosSuite) find(query domain.ToDoQuery) *domain.ToDoPage {
+	want, err := s.memory.FindToDos(&query)
+	s.Require().NoError(err)
+	got, err := s.sqlite.FindToDos(&query)
+	s.Require().NoError(err)
+	s.Equal(want, got, "%+v", query)
+	return got
+}
+
+// execute is find through FindToDosUseCase, which applies the page size
+// defaults and bounds.
+func (s *FindToDosSuite) execute(input usecase.FindToDosInputDTO) (*usecase.FindToDosOutputDTO, error) {
+	want, wantErr := usecase.NewFindToDosUseCase(s.memory).Execute(&input)
+	got, err := usecase.NewFindToDosUseCase(s.sqlite).Execute(&input)
+	s.Equal(wantErr, err, "%+v", input)
+	s.Equal(want, got, "%+v", input)
+	return got, err
+}
+
+func (s *FindToDosSuite) TestFilters() {
+	completed, pending := true, false
+	for _, query := range []domain.ToDoQuery{
+		{},
+		{Deleted: true},
+		{Completed: &completed},
+		{Completed: &pending},
+		{Owner: &owners[1]},
+		{Owner: &owners[2], Completed: &completed, Deleted: true},
+		{CreatedFrom: 1010},
+		{CreatedTo: 1010},
+		{CreatedFrom: 1010, CreatedTo: 1020},
+		{UpdatedFrom: 2010},
+		{UpdatedTo: 2010},
+		{UpdatedFrom: 2010, UpdatedTo: 2020},
+	} {
+		page := s.find(query)
+		s.NotEmpty(page.ToDos, "%+v", query)
+		s.Len(page.ToDos, page.Total, "%+v", query)
+		for _, toDo := range page.ToDos {
+			s.True(query.Match(toDo), "%+v", query)
+		}
+	}
+}
+
+func (s *FindToDosSuite) TestSortKeys() {
+	for _, sortBy := range []domain.ToDoSort{"", domain.SortToDosById, domain.SortToDosByCreatedAt, domain.SortToDosByUpdatedAt} {
+		for _, descending := range []bool{false, true} {
+			query := domain.ToDoQuery{SortBy: sortBy, Descending: descending}
+			page := s.find(query)
+			s.Len(page.ToDos, 108)
+			for i := 1; i < len(page.ToDos); i++ {
+				s.True(query.Less(page.ToDos[i-1], page.ToDos[i]), "%+v", query)
+			}
+		}
+	}
+}
+
+func (s *FindToDosSuite) TestPaging() {
+	page := s.find(domain.ToDoQuery{Offset: 100, Limit: 5})
+	s.Equal(108, page.Total)
+	s.Len(page.ToDos, 5)
+	s.Equal(uint(112), page.ToDos[0].Id)
+
+	page = s.find(domain.ToDoQuery{Offset: 105, Limit: 5})
+	s.Len(page.ToDos, 3)
+
+	page = s.find(domain.ToDoQuery{Offset: 500, Limit: 5})
+	s.Equal(108, page.Total)
+	s.Empty(page.ToDos)
+
+	page = s.find(domain.ToDoQuery{SortBy: domain.SortToDosByUpdatedAt, Descending: true, Offset: 108})
+	s.Empty(page.ToDos)
+}
+
+func (s *FindToDosSuite) TestPageSize() {
+	res, err := s.execute(usecase.FindToDosInputDTO{})
+	s.Require().NoError(err)
+	s.Equal(usecase.DefaultToDoPageSize, res.Limit)
+	s.Len(res.ToDos, usecase.DefaultToDoPageSize)
+	s.Equal(108, res.Total)
+
+	res, err = s.execute(usecase.FindToDosInputDTO{Limit: usecase.MaxToDoPageSize})
+	s.Require().NoError(err)
+	s.Len(res.ToDos, usecase.MaxToDoPageSize)
+
+	_, err = s.execute(usecase.FindToDosInputDTO{Limit: usecase.MaxToDoPageSize + 1})
+	s.ErrorIs(err, domain.ErrInvalidQuery)
+}
+
+// Both backends hand out copies: changing a result must not change what a
+// later query returns.
+func (s *FindToDosSuite) TestResultsAreCopies() {
+	for _, repo := range []repository.ToDoRepository{s.sqlite, s.memory} {
+		all, err := repo.FindAllToDos()
+		s.Require().NoError(err)
+		all[0].Title = "changed"
+		page, err := repo.FindToDos(&domain.ToDoQuery{Limit: 1})
+		s.Require().NoError(err)
+		s.Equal("title", page.ToDos[0].Title)
+		page.ToDos[0].Completed = !page.ToDos[0].Completed
+		all, err = repo.FindAllToDos()
+		s.Require().NoError(err)
+		s.Equal("title", all[0].Title)
+	}
+}
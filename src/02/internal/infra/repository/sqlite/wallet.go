@@ -0,0 +1,98 @@
+package sqlite
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/wallet"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Balances are stored as decimal strings since uint256 does not fit in any
+// SQLite integer column.
+type etherBalance struct {
+	Owner string `gorm:"primaryKey"`
+	Value string `gorm:"not null"`
+}
+
+type erc20Balance struct {
+	Token string `gorm:"primaryKey"`
+	Owner string `gorm:"primaryKey"`
+	Value string `gorm:"not null"`
+}
+
+type erc721Owner struct {
+	Token   string `gorm:"primaryKey"`
+	TokenId string `gorm:"primaryKey"`
+	Owner   string `gorm:"not null"`
+}
+
+func (r *SQLiteRepository) FindEtherBalance(owner common.Address) (*big.Int, error) {
+	var balance etherBalance
+	err := r.Db.Where("owner = ?", owner.Hex()).Take(&balance).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return new(big.Int), nil
+		}
+		return nil, fmt.Errorf("failed to find ether balance: %w", err)
+	}
+	return parseBalance(balance.Value)
+}
+
+func (r *SQLiteRepository) UpdateEtherBalance(owner common.Address, value *big.Int) error {
+	balance := etherBalance{Owner: owner.Hex(), Value: value.String()}
+	if err := r.Db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&balance).Error; err != nil {
+		return fmt.Errorf("failed to update ether balance: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) FindERC20Balance(token common.Address, owner common.Address) (*big.Int, error) {
+	var balance erc20Balance
+	err := r.Db.Where("token = ? AND owner = ?", token.Hex(), owner.Hex()).Take(&balance).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return new(big.Int), nil
+		}
+		return nil, fmt.Errorf("failed to find erc20 balance: %w", err)
+	}
+	return parseBalance(balance.Value)
+}
+
+func (r *SQLiteRepository) UpdateERC20Balance(token common.Address, owner common.Address, value *big.Int) error {
+	balance := erc20Balance{Token: token.Hex(), Owner: owner.Hex(), Value: value.String()}
+	if err := r.Db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&balance).Error; err != nil {
+		return fmt.Errorf("failed to update erc20 balance: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteRepository) FindERC721Owner(token common.Address, tokenId *big.Int) (common.Address, error) {
+	var owner erc721Owner
+	err := r.Db.Where("token = ? AND token_id = ?", token.Hex(), tokenId.String()).Take(&owner).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return common.Address{}, nil
+		}
+		return common.Address{}, fmt.Errorf("failed to find erc721 owner: %w", err)
+	}
+	return common.HexToAddress(owner.Owner), nil
+}
+
+func (r *SQLiteRepository) UpdateERC721Owner(token common.Address, tokenId *big.Int, owner common.Address) error {
+	row := erc721Owner{Token: token.Hex(), TokenId: tokenId.String(), Owner: owner.Hex()}
+	if err := r.Db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&row).Error; err != nil {
+		return fmt.Errorf("failed to update erc721 owner: %w", err)
+	}
+	return nil
+}
+
+func parseBalance(value string) (*big.Int, error) {
+	balance, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: failed to parse stored balance: %q", wallet.ErrCorruptedState, value)
+	}
+	return balance, nil
+}
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
 	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
 	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
@@ -12,11 +13,12 @@ type CreateToDoInputDTO struct {
 }
 
 type CreateToDoOutputDTO struct {
-	Id          uint   `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Completed   bool   `json:"completed"`
-	CreatedAt   uint64 `json:"created_at"`
+	Id          uint           `json:"id"`
+	Owner       common.Address `json:"owner"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Completed   bool           `json:"completed"`
+	CreatedAt   uint64         `json:"created_at"`
 }
 
 type CreateToDoUseCase struct {
@@ -30,7 +32,7 @@ func NewCreateToDoUseCase(todoRepository repository.ToDoRepository) *CreateToDoU
 }
 
 func (u *CreateToDoUseCase) Execute(input *CreateToDoInputDTO, metadata rollups.Metadata) (*CreateToDoOutputDTO, error) {
-	res, err := domain.NewToDo(input.Title, input.Description, metadata.BlockTimestamp)
+	res, err := domain.NewToDo(metadata.MsgSender, input.Title, input.Description, metadata.BlockTimestamp)
 	if err != nil {
 		return nil, err
 	}
@@ -39,9 +41,13 @@ func (u *CreateToDoUseCase) Execute(input *CreateToDoInputDTO, metadata rollups.
 	if err != nil {
 		return nil, err
 	}
+	if err := recordChange(u.ToDoRepository, domain.ToDoCreated, nil, res, metadata); err != nil {
+		return nil, err
+	}
 
 	return &CreateToDoOutputDTO{
 		Id:          res.Id,
+		Owner:       res.Owner,
 		Title:       res.Title,
 		Description: res.Description,
 		Completed:   res.Completed,
@@ -1,11 +1,21 @@
 package usecase
 
-import "github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+import (
+	"fmt"
+
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+)
 
 type DeleteToDoInputDTO struct {
 	Id uint `json:"id" validate:"required"`
 }
 
+type DeleteToDoOutputDTO struct {
+	Id uint `json:"id"`
+}
+
 type DeleteToDoUseCase struct {
 	ToDoRepository repository.ToDoRepository
 }
@@ -16,6 +26,27 @@ func NewDeleteToDoUseCase(todoRepository repository.ToDoRepository) *DeleteToDoU
 	}
 }
 
-func (u *DeleteToDoUseCase) Execute(input *DeleteToDoInputDTO) error {
-	return u.ToDoRepository.DeleteToDo(input.Id)
+func (u *DeleteToDoUseCase) Execute(input *DeleteToDoInputDTO, metadata rollups.Metadata) (*DeleteToDoOutputDTO, error) {
+	var before domain.ToDo
+	res, err := u.ToDoRepository.UpdateToDo(input.Id, func(toDo *domain.ToDo) error {
+		if toDo.IsDeleted() {
+			return fmt.Errorf("%w: todo %d was deleted", domain.ErrNotFound, input.Id)
+		}
+		if err := toDo.CheckOwner(metadata.MsgSender); err != nil {
+			return err
+		}
+		// The todo is only marked deleted, so restoreToDo can bring it back.
+		before = *toDo
+		toDo.DeletedAt = metadata.BlockTimestamp
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := recordChange(u.ToDoRepository, domain.ToDoDeleted, &before, res, metadata); err != nil {
+		return nil, err
+	}
+	return &DeleteToDoOutputDTO{
+		Id: input.Id,
+	}, nil
 }
@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/wallet"
+)
+
+type DepositAssetUseCase struct {
+	WalletRepository repository.WalletRepository
+}
+
+func NewDepositAssetUseCase(walletRepository repository.WalletRepository) *DepositAssetUseCase {
+	return &DepositAssetUseCase{
+		WalletRepository: walletRepository,
+	}
+}
+
+func (u *DepositAssetUseCase) Execute(deposit rollups.Deposit) error {
+	return wallet.NewWallet(u.WalletRepository).Deposit(deposit)
+}
@@ -1,14 +1,20 @@
 package usecase
 
-import "github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+)
 
 type FindToDoOutputDTO struct {
-	Id          uint   `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Completed   bool   `json:"completed"`
-	CreatedAt   uint64 `json:"created_at"`
-	UpdatedAt   uint64 `json:"updated_at"`
+	Id          uint           `json:"id"`
+	Owner       common.Address `json:"owner"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Completed   bool           `json:"completed"`
+	CreatedAt   uint64         `json:"created_at"`
+	UpdatedAt   uint64         `json:"updated_at"`
+	DeletedAt   uint64         `json:"deleted_at,omitempty"`
 }
 
 type FindAllToDosOutputDTO []*FindToDoOutputDTO
@@ -28,16 +34,22 @@ func (u *FindAllToDosUseCase) Execute() (*FindAllToDosOutputDTO, error) {
 	if err != nil {
 		return nil, err
 	}
-	output := make(FindAllToDosOutputDTO, len(res))
-	for i, todo := range res {
+	return newFindAllToDosOutputDTO(res), nil
+}
+
+func newFindAllToDosOutputDTO(toDos []*domain.ToDo) *FindAllToDosOutputDTO {
+	output := make(FindAllToDosOutputDTO, len(toDos))
+	for i, todo := range toDos {
 		output[i] = &FindToDoOutputDTO{
 			Id:          todo.Id,
+			Owner:       todo.Owner,
 			Title:       todo.Title,
 			Description: todo.Description,
 			Completed:   todo.Completed,
 			CreatedAt:   todo.CreatedAt,
 			UpdatedAt:   todo.UpdatedAt,
+			DeletedAt:   todo.DeletedAt,
 		}
 	}
-	return &output, nil
+	return &output
 }
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/wallet"
+)
+
+type FindBalanceInputDTO struct {
+	Asset   string
+	Token   common.Address
+	Owner   common.Address
+	TokenId *big.Int
+}
+
+type FindBalanceOutputDTO struct {
+	Asset   string         `json:"asset"`
+	Token   common.Address `json:"token,omitempty"`
+	Owner   common.Address `json:"owner"`
+	Balance *big.Int       `json:"balance,omitempty"`
+	TokenId *big.Int       `json:"token_id,omitempty"`
+}
+
+type FindBalanceUseCase struct {
+	WalletRepository repository.WalletRepository
+}
+
+func NewFindBalanceUseCase(walletRepository repository.WalletRepository) *FindBalanceUseCase {
+	return &FindBalanceUseCase{
+		WalletRepository: walletRepository,
+	}
+}
+
+// Execute returns the balance of Owner, or the owner of TokenId for ERC721.
+func (u *FindBalanceUseCase) Execute(input *FindBalanceInputDTO) (*FindBalanceOutputDTO, error) {
+	w := wallet.NewWallet(u.WalletRepository)
+	output := &FindBalanceOutputDTO{
+		Asset: input.Asset,
+		Owner: input.Owner,
+	}
+	var err error
+	switch input.Asset {
+	case AssetEther:
+		output.Balance, err = w.EtherBalanceOf(input.Owner)
+	case AssetERC20:
+		output.Token = input.Token
+		output.Balance, err = w.ERC20BalanceOf(input.Token, input.Owner)
+	case AssetERC721:
+		output.Token, output.TokenId = input.Token, input.TokenId
+		output.Owner, err = w.ERC721OwnerOf(input.Token, input.TokenId)
+	default:
+		err = fmt.Errorf("%w: %s", wallet.ErrUnsupportedAsset, input.Asset)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+)
+
+type FindToDoByIdInputDTO struct {
+	Id uint `json:"id" validate:"required"`
+}
+
+type FindToDoByIdUseCase struct {
+	ToDoRepository repository.ToDoRepository
+}
+
+func NewFindToDoByIdUseCase(todoRepository repository.ToDoRepository) *FindToDoByIdUseCase {
+	return &FindToDoByIdUseCase{
+		ToDoRepository: todoRepository,
+	}
+}
+
+func (u *FindToDoByIdUseCase) Execute(input *FindToDoByIdInputDTO) (*FindToDoOutputDTO, error) {
+	res, err := findLiveToDo(u.ToDoRepository, input.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &FindToDoOutputDTO{
+		Id:          res.Id,
+		Owner:       res.Owner,
+		Title:       res.Title,
+		Description: res.Description,
+		Completed:   res.Completed,
+		CreatedAt:   res.CreatedAt,
+		UpdatedAt:   res.UpdatedAt,
+	}, nil
+}
+
+// findLiveToDo is FindToDoById for todos that have not been deleted.
+func findLiveToDo(repo repository.ToDoRepository, id uint) (*domain.ToDo, error) {
+	toDo, err := repo.FindToDoById(id)
+	if err != nil {
+		return nil, err
+	}
+	if toDo.IsDeleted() {
+		return nil, fmt.Errorf("%w: todo %d was deleted", domain.ErrNotFound, id)
+	}
+	return toDo, nil
+}
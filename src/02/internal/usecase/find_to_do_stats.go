@@ -0,0 +1,34 @@
+package usecase
+
+import "github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+
+type FindToDoStatsOutputDTO struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Pending   int `json:"pending"`
+}
+
+type FindToDoStatsUseCase struct {
+	ToDoRepository repository.ToDoRepository
+}
+
+func NewFindToDoStatsUseCase(todoRepository repository.ToDoRepository) *FindToDoStatsUseCase {
+	return &FindToDoStatsUseCase{
+		ToDoRepository: todoRepository,
+	}
+}
+
+func (u *FindToDoStatsUseCase) Execute() (*FindToDoStatsOutputDTO, error) {
+	res, err := u.ToDoRepository.FindAllToDos()
+	if err != nil {
+		return nil, err
+	}
+	output := &FindToDoStatsOutputDTO{Total: len(res)}
+	for _, todo := range res {
+		if todo.Completed {
+			output.Completed++
+		}
+	}
+	output.Pending = output.Total - output.Completed
+	return output, nil
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+)
+
+const (
+	DefaultToDoPageSize = 50
+	MaxToDoPageSize     = 100
+)
+
+// FindToDosInputDTO filters, sorts and pages todos. Omitted fields do not
+// filter; timestamp ranges are inclusive and sort_by is one of id (the
+// default), created_at or updated_at. deleted lists deleted todos instead
+// of live ones.
+type FindToDosInputDTO struct {
+	Deleted     bool            `json:"deleted,omitempty"`
+	Completed   *bool           `json:"completed,omitempty"`
+	Owner       *common.Address `json:"owner,omitempty"`
+	CreatedFrom uint64          `json:"created_from,omitempty"`
+	CreatedTo   uint64          `json:"created_to,omitempty"`
+	UpdatedFrom uint64          `json:"updated_from,omitempty"`
+	UpdatedTo   uint64          `json:"updated_to,omitempty"`
+	SortBy      domain.ToDoSort `json:"sort_by,omitempty"`
+	Order       string          `json:"order,omitempty"`
+	Offset      int             `json:"offset,omitempty"`
+	Limit       int             `json:"limit,omitempty"`
+}
+
+type FindToDosOutputDTO struct {
+	ToDos  FindAllToDosOutputDTO `json:"todos"`
+	Total  int                   `json:"total"`
+	Offset int                   `json:"offset"`
+	Limit  int                   `json:"limit"`
+}
+
+type FindToDosUseCase struct {
+	ToDoRepository repository.ToDoRepository
+}
+
+func NewFindToDosUseCase(todoRepository repository.ToDoRepository) *FindToDosUseCase {
+	return &FindToDosUseCase{
+		ToDoRepository: todoRepository,
+	}
+}
+
+func (u *FindToDosUseCase) Execute(input *FindToDosInputDTO) (*FindToDosOutputDTO, error) {
+	query := &domain.ToDoQuery{
+		Deleted:     input.Deleted,
+		Completed:   input.Completed,
+		Owner:       input.Owner,
+		CreatedFrom: input.CreatedFrom,
+		CreatedTo:   input.CreatedTo,
+		UpdatedFrom: input.UpdatedFrom,
+		UpdatedTo:   input.UpdatedTo,
+		SortBy:      input.SortBy,
+		Offset:      input.Offset,
+		Limit:       input.Limit,
+	}
+	switch input.Order {
+	case "", "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		return nil, fmt.Errorf("%w: order must be asc or desc, not %q", domain.ErrInvalidQuery, input.Order)
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultToDoPageSize
+	}
+	if query.Limit > MaxToDoPageSize {
+		return nil, fmt.Errorf("%w: limit %d is above %d", domain.ErrInvalidQuery, query.Limit, MaxToDoPageSize)
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	res, err := u.ToDoRepository.FindToDos(query)
+	if err != nil {
+		return nil, err
+	}
+	return &FindToDosOutputDTO{
+		ToDos:  *newFindAllToDosOutputDTO(res.ToDos),
+		Total:  res.Total,
+		Offset: query.Offset,
+		Limit:  query.Limit,
+	}, nil
+}
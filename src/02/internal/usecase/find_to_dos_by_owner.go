@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+)
+
+type FindToDosByOwnerInputDTO struct {
+	Owner common.Address `json:"owner"`
+}
+
+type FindToDosByOwnerUseCase struct {
+	ToDoRepository repository.ToDoRepository
+}
+
+func NewFindToDosByOwnerUseCase(todoRepository repository.ToDoRepository) *FindToDosByOwnerUseCase {
+	return &FindToDosByOwnerUseCase{
+		ToDoRepository: todoRepository,
+	}
+}
+
+func (u *FindToDosByOwnerUseCase) Execute(input *FindToDosByOwnerInputDTO) (*FindAllToDosOutputDTO, error) {
+	res, err := u.ToDoRepository.FindToDos(&domain.ToDoQuery{Owner: &input.Owner})
+	if err != nil {
+		return nil, err
+	}
+	return newFindAllToDosOutputDTO(res.ToDos), nil
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"math/big"
+
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+)
+
+// Notice schemas of the todo application. Notices carry these ABI tuples
+// instead of JSON so they can be decoded on-chain once validated; see
+// contracts/ToDoNotices.sol. Changing a tuple means registering a new
+// version, never editing an existing one.
+var (
+	ToDoCreatedNotice  = rollups.RegisterNotice(1, "ToDoCreated(uint256,address,string,uint64)")
+	ToDoUpdatedNotice  = rollups.RegisterNotice(1, "ToDoUpdated(uint256,string,bool,uint64)")
+	ToDoDeletedNotice  = rollups.RegisterNotice(1, "ToDoDeleted(uint256)")
+	ToDoRestoredNotice = rollups.RegisterNotice(1, "ToDoRestored(uint256,uint64)")
+)
+
+func (o *CreateToDoOutputDTO) MarshalNotice() ([]byte, error) {
+	return ToDoCreatedNotice.Encode(new(big.Int).SetUint64(uint64(o.Id)), o.Owner, o.Title, o.CreatedAt)
+}
+
+func (o *UpdateToDoOutputDTO) MarshalNotice() ([]byte, error) {
+	return ToDoUpdatedNotice.Encode(new(big.Int).SetUint64(uint64(o.Id)), o.Title, o.Completed, o.UpdatedAt)
+}
+
+func (o *DeleteToDoOutputDTO) MarshalNotice() ([]byte, error) {
+	return ToDoDeletedNotice.Encode(new(big.Int).SetUint64(uint64(o.Id)))
+}
+
+func (o *RestoreToDoOutputDTO) MarshalNotice() ([]byte, error) {
+	return ToDoRestoredNotice.Encode(new(big.Int).SetUint64(uint64(o.Id)), o.RestoredAt)
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+)
+
+type RestoreToDoInputDTO struct {
+	Id uint `json:"id" validate:"required"`
+}
+
+type RestoreToDoOutputDTO struct {
+	Id         uint   `json:"id"`
+	RestoredAt uint64 `json:"restored_at"`
+}
+
+type RestoreToDoUseCase struct {
+	ToDoRepository repository.ToDoRepository
+}
+
+func NewRestoreToDoUseCase(todoRepository repository.ToDoRepository) *RestoreToDoUseCase {
+	return &RestoreToDoUseCase{
+		ToDoRepository: todoRepository,
+	}
+}
+
+func (u *RestoreToDoUseCase) Execute(input *RestoreToDoInputDTO, metadata rollups.Metadata) (*RestoreToDoOutputDTO, error) {
+	var before domain.ToDo
+	res, err := u.ToDoRepository.UpdateToDo(input.Id, func(toDo *domain.ToDo) error {
+		if !toDo.IsDeleted() {
+			return fmt.Errorf("%w: todo %d is not deleted", domain.ErrNotFound, input.Id)
+		}
+		if err := toDo.CheckOwner(metadata.MsgSender); err != nil {
+			return err
+		}
+		before = *toDo
+		toDo.DeletedAt = 0
+		toDo.UpdatedAt = metadata.BlockTimestamp
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := recordChange(u.ToDoRepository, domain.ToDoRestored, &before, res, metadata); err != nil {
+		return nil, err
+	}
+	return &RestoreToDoOutputDTO{
+		Id:         res.Id,
+		RestoredAt: metadata.BlockTimestamp,
+	}, nil
+}
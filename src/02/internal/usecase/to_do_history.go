@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+)
+
+type FindToDoHistoryInputDTO struct {
+	Id uint `json:"id" validate:"required"`
+}
+
+type ToDoChangeOutputDTO struct {
+	Action     domain.ToDoAction        `json:"action"`
+	InputIndex uint64                   `json:"input_index"`
+	MsgSender  common.Address           `json:"msg_sender"`
+	Timestamp  uint64                   `json:"timestamp"`
+	Diff       []domain.ToDoFieldChange `json:"diff"`
+}
+
+type FindToDoHistoryOutputDTO []*ToDoChangeOutputDTO
+
+type FindToDoHistoryUseCase struct {
+	ToDoRepository repository.ToDoRepository
+}
+
+func NewFindToDoHistoryUseCase(todoRepository repository.ToDoRepository) *FindToDoHistoryUseCase {
+	return &FindToDoHistoryUseCase{
+		ToDoRepository: todoRepository,
+	}
+}
+
+// Execute returns the changes made to a todo, oldest first. Deleted todos
+// keep their history.
+func (u *FindToDoHistoryUseCase) Execute(input *FindToDoHistoryInputDTO) (*FindToDoHistoryOutputDTO, error) {
+	if _, err := u.ToDoRepository.FindToDoById(input.Id); err != nil {
+		return nil, err
+	}
+	res, err := u.ToDoRepository.FindToDoChanges(input.Id)
+	if err != nil {
+		return nil, err
+	}
+	output := make(FindToDoHistoryOutputDTO, len(res))
+	for i, change := range res {
+		output[i] = &ToDoChangeOutputDTO{
+			Action:     change.Action,
+			InputIndex: change.InputIndex,
+			MsgSender:  change.MsgSender,
+			Timestamp:  change.Timestamp,
+			Diff:       change.Diff,
+		}
+	}
+	return &output, nil
+}
+
+// recordChange appends to the todo history the change the input described
+// by metadata made to it. before is nil for a created todo.
+func recordChange(repo repository.ToDoRepository, action domain.ToDoAction, before, after *domain.ToDo, metadata rollups.Metadata) error {
+	return repo.AppendToDoChange(&domain.ToDoChange{
+		ToDoId:     after.Id,
+		Action:     action,
+		InputIndex: metadata.InputIndex,
+		MsgSender:  metadata.MsgSender,
+		Timestamp:  metadata.BlockTimestamp,
+		Diff:       domain.DiffToDos(before, after),
+	})
+}
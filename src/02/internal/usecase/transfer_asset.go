@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/wallet"
+)
+
+const (
+	AssetEther  = "ether"
+	AssetERC20  = "erc20"
+	AssetERC721 = "erc721"
+)
+
+type TransferAssetInputDTO struct {
+	Asset   string         `json:"asset" validate:"required,oneof=ether erc20 erc721"`
+	Token   common.Address `json:"token" validate:"required_unless=Asset ether"`
+	To      common.Address `json:"to" validate:"required"`
+	Value   *big.Int       `json:"value" validate:"required_unless=Asset erc721"`
+	TokenId *big.Int       `json:"token_id" validate:"required_if=Asset erc721"`
+}
+
+type TransferAssetOutputDTO struct {
+	Asset   string         `json:"asset"`
+	Token   common.Address `json:"token,omitempty"`
+	From    common.Address `json:"from"`
+	To      common.Address `json:"to"`
+	Value   *big.Int       `json:"value,omitempty"`
+	TokenId *big.Int       `json:"token_id,omitempty"`
+}
+
+type TransferAssetUseCase struct {
+	WalletRepository repository.WalletRepository
+}
+
+func NewTransferAssetUseCase(walletRepository repository.WalletRepository) *TransferAssetUseCase {
+	return &TransferAssetUseCase{
+		WalletRepository: walletRepository,
+	}
+}
+
+func (u *TransferAssetUseCase) Execute(input *TransferAssetInputDTO, metadata rollups.Metadata) (*TransferAssetOutputDTO, error) {
+	w := wallet.NewWallet(u.WalletRepository)
+	from := metadata.MsgSender
+	output := &TransferAssetOutputDTO{
+		Asset: input.Asset,
+		From:  from,
+		To:    input.To,
+	}
+	switch input.Asset {
+	case AssetEther:
+		if err := w.EtherTransfer(from, input.To, input.Value); err != nil {
+			return nil, err
+		}
+		output.Value = input.Value
+	case AssetERC20:
+		if err := w.ERC20Transfer(input.Token, from, input.To, input.Value); err != nil {
+			return nil, err
+		}
+		output.Token, output.Value = input.Token, input.Value
+	case AssetERC721:
+		if err := w.ERC721Transfer(input.Token, from, input.To, input.TokenId); err != nil {
+			return nil, err
+		}
+		output.Token, output.TokenId = input.Token, input.TokenId
+	default:
+		return nil, fmt.Errorf("%w: %s", wallet.ErrUnsupportedAsset, input.Asset)
+	}
+	return output, nil
+}
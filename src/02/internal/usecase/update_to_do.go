@@ -1,16 +1,25 @@
 package usecase
 
 import (
+	"fmt"
+
 	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/domain"
 	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
 	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
 )
 
+// UpdateToDoInputDTO changes only the fields that are present, so a client
+// can toggle completed without resending title and description.
 type UpdateToDoInputDTO struct {
-	Id          uint   `json:"id" validate:"required"`
-	Title       string `json:"title" validate:"required"`
-	Description string `json:"description" validate:"required"`
-	Completed   bool   `json:"completed" validate:"required"`
+	Id          uint    `json:"id" validate:"required"`
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Completed   *bool   `json:"completed,omitempty"`
+}
+
+// ToggleToDoInputDTO names the todo to complete or reopen.
+type ToggleToDoInputDTO struct {
+	Id uint `json:"id" validate:"required"`
 }
 
 type UpdateToDoOutputDTO struct {
@@ -33,16 +42,36 @@ func NewUpdateToDoUseCase(todoRepository repository.ToDoRepository) *UpdateToDoU
 }
 
 func (u *UpdateToDoUseCase) Execute(input *UpdateToDoInputDTO, metadata rollups.Metadata) (*UpdateToDoOutputDTO, error) {
-	res, err := u.ToDoRepository.UpdateToDo(&domain.ToDo{
-		Id:          input.Id,
-		Title:       input.Title,
-		Description: input.Description,
-		Completed:   input.Completed,
-		UpdatedAt:   metadata.BlockTimestamp,
+	if input.Title == nil && input.Description == nil && input.Completed == nil {
+		return nil, fmt.Errorf("%w: nothing to update", domain.ErrInvalidToDo)
+	}
+	var before domain.ToDo
+	res, err := u.ToDoRepository.UpdateToDo(input.Id, func(toDo *domain.ToDo) error {
+		if toDo.IsDeleted() {
+			return fmt.Errorf("%w: todo %d was deleted", domain.ErrNotFound, input.Id)
+		}
+		if err := toDo.CheckOwner(metadata.MsgSender); err != nil {
+			return err
+		}
+		before = *toDo
+		if input.Title != nil {
+			toDo.Title = *input.Title
+		}
+		if input.Description != nil {
+			toDo.Description = *input.Description
+		}
+		if input.Completed != nil {
+			toDo.Completed = *input.Completed
+		}
+		toDo.UpdatedAt = metadata.BlockTimestamp
+		return toDo.Validate()
 	})
 	if err != nil {
 		return nil, err
 	}
+	if err := recordChange(u.ToDoRepository, domain.ToDoUpdated, &before, res, metadata); err != nil {
+		return nil, err
+	}
 	return &UpdateToDoOutputDTO{
 		Id:          res.Id,
 		Title:       res.Title,
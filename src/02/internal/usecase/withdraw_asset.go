@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/internal/infra/repository"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/wallet"
+)
+
+type WithdrawAssetInputDTO struct {
+	Asset   string         `json:"asset" validate:"required,oneof=ether erc20 erc721"`
+	Token   common.Address `json:"token" validate:"required_unless=Asset ether"`
+	Value   *big.Int       `json:"value" validate:"required_unless=Asset erc721"`
+	TokenId *big.Int       `json:"token_id" validate:"required_if=Asset erc721"`
+}
+
+type WithdrawAssetOutputDTO struct {
+	Asset   string         `json:"asset"`
+	Token   common.Address `json:"token,omitempty"`
+	Owner   common.Address `json:"owner"`
+	Value   *big.Int       `json:"value,omitempty"`
+	TokenId *big.Int       `json:"token_id,omitempty"`
+}
+
+type WithdrawAssetUseCase struct {
+	WalletRepository repository.WalletRepository
+}
+
+func NewWithdrawAssetUseCase(walletRepository repository.WalletRepository) *WithdrawAssetUseCase {
+	return &WithdrawAssetUseCase{
+		WalletRepository: walletRepository,
+	}
+}
+
+// Execute debits the asset from msg_sender and returns the voucher that
+// releases it on the base layer.
+func (u *WithdrawAssetUseCase) Execute(input *WithdrawAssetInputDTO, metadata rollups.Metadata) (*WithdrawAssetOutputDTO, *rollups.VoucherRequest, error) {
+	w := wallet.NewWallet(u.WalletRepository)
+	owner := metadata.MsgSender
+	output := &WithdrawAssetOutputDTO{
+		Asset: input.Asset,
+		Owner: owner,
+	}
+	var voucher *rollups.VoucherRequest
+	var err error
+	switch input.Asset {
+	case AssetEther:
+		voucher, err = w.EtherWithdraw(owner, input.Value)
+		output.Value = input.Value
+	case AssetERC20:
+		voucher, err = w.ERC20Withdraw(input.Token, owner, input.Value)
+		output.Token, output.Value = input.Token, input.Value
+	case AssetERC721:
+		voucher, err = w.ERC721Withdraw(metadata.AppContract, input.Token, owner, input.TokenId)
+		output.Token, output.TokenId = input.Token, input.TokenId
+	default:
+		err = fmt.Errorf("%w: %s", wallet.ErrUnsupportedAsset, input.Asset)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return output, voucher, nil
+}
@@ -0,0 +1,213 @@
+// Package acl restricts router paths to accounts holding a role. Roles are
+// kept in application state, granted and revoked by admins through inputs,
+// and checked by route guards against the input msg_sender.
+package acl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+)
+
+type Role string
+
+const (
+	RoleAdmin    Role = "admin"
+	RoleOperator Role = "operator"
+	RoleUser     Role = "user"
+)
+
+var (
+	ErrMissingRole = errors.New("missing role")
+	ErrUnknownRole = errors.New("unknown role")
+	ErrLastAdmin   = errors.New("cannot revoke the last admin")
+)
+
+func (r Role) Validate() error {
+	switch r {
+	case RoleAdmin, RoleOperator, RoleUser:
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownRole, string(r))
+}
+
+type Assignment struct {
+	Account common.Address `json:"account"`
+	Role    Role           `json:"role"`
+}
+
+// Store keeps role assignments. FindRoleAssignments returns them in a
+// deterministic order so reports match across nodes.
+type Store interface {
+	HasRole(account common.Address, role Role) (bool, error)
+	GrantRole(account common.Address, role Role) error
+	RevokeRole(account common.Address, role Role) error
+	FindRoleAssignments() ([]*Assignment, error)
+}
+
+type ACL struct {
+	store Store
+}
+
+func New(store Store) *ACL {
+	return &ACL{store: store}
+}
+
+// Bootstrap grants the admin role to the configured accounts, but only while
+// no role has been assigned. Once state holds roles they change through
+// grantRole and revokeRole alone, so a restart does not grant again an admin
+// that was revoked.
+func (a *ACL) Bootstrap(admins ...common.Address) error {
+	assignments, err := a.store.FindRoleAssignments()
+	if err != nil {
+		return fmt.Errorf("failed to bootstrap admins: %w", err)
+	}
+	if len(assignments) > 0 {
+		return nil
+	}
+	for _, admin := range admins {
+		if err := a.store.GrantRole(admin, RoleAdmin); err != nil {
+			return fmt.Errorf("failed to bootstrap admin %s: %w", admin, err)
+		}
+	}
+	return nil
+}
+
+// ParseAccounts parses a comma-separated list of addresses, such as the
+// ADMIN_ADDRESSES setting. Blanks around each address are ignored.
+func ParseAccounts(value string) ([]common.Address, error) {
+	var accounts []common.Address
+	for _, address := range strings.Split(value, ",") {
+		address = strings.TrimSpace(address)
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid account %q", address)
+		}
+		accounts = append(accounts, common.HexToAddress(address))
+	}
+	return accounts, nil
+}
+
+func (a *ACL) HasRole(account common.Address, role Role) (bool, error) {
+	return a.store.HasRole(account, role)
+}
+
+func (a *ACL) Grant(account common.Address, role Role) error {
+	if err := role.Validate(); err != nil {
+		return err
+	}
+	return a.store.GrantRole(account, role)
+}
+
+// Revoke removes role from account. The last admin cannot be revoked, as
+// nobody would be left to grant roles.
+func (a *ACL) Revoke(account common.Address, role Role) error {
+	if err := role.Validate(); err != nil {
+		return err
+	}
+	if role == RoleAdmin {
+		assignments, err := a.store.FindRoleAssignments()
+		if err != nil {
+			return err
+		}
+		admins := 0
+		revoked := false
+		for _, assignment := range assignments {
+			if assignment.Role == RoleAdmin {
+				admins++
+				revoked = revoked || assignment.Account == account
+			}
+		}
+		if revoked && admins == 1 {
+			return ErrLastAdmin
+		}
+	}
+	return a.store.RevokeRole(account, role)
+}
+
+// Require guards a route: inputs whose msg_sender holds none of roles are
+// rejected with ErrMissingRole.
+func (a *ACL) Require(roles ...Role) rollups.Middleware {
+	return func(next rollups.AdvanceHandlerFunc) rollups.AdvanceHandlerFunc {
+		return func(ctx context.Context, payload []byte, metadata rollups.Metadata) error {
+			for _, role := range roles {
+				ok, err := a.store.HasRole(metadata.MsgSender, role)
+				if err != nil {
+					return fmt.Errorf("failed to check role %s of %s: %w", role, metadata.MsgSender, err)
+				}
+				if ok {
+					return next(ctx, payload, metadata)
+				}
+			}
+			return fmt.Errorf("%w: %s is not %v", ErrMissingRole, metadata.MsgSender, roles)
+		}
+	}
+}
+
+// RoleInputDTO is the payload of the grantRole and revokeRole paths.
+type RoleInputDTO struct {
+	Account common.Address `json:"account" validate:"required"`
+	Role    Role           `json:"role" validate:"required"`
+}
+
+// RoleOutputDTO is the notice emitted when a role changes.
+type RoleOutputDTO struct {
+	Account common.Address `json:"account"`
+	Role    Role           `json:"role"`
+	Granted bool           `json:"granted"`
+}
+
+// Register adds the admin-only grantRole and revokeRole paths and the roles
+// inspect path, which reports every assignment.
+func (a *ACL) Register(r *rollups.Router) {
+	rollups.HandleJSON(r, "grantRole", func(ctx context.Context, input RoleInputDTO, metadata rollups.Metadata) (*RoleOutputDTO, error) {
+		if err := a.Grant(input.Account, input.Role); err != nil {
+			return nil, err
+		}
+		return &RoleOutputDTO{Account: input.Account, Role: input.Role, Granted: true}, nil
+	}, a.Require(RoleAdmin))
+	rollups.HandleJSON(r, "revokeRole", func(ctx context.Context, input RoleInputDTO, metadata rollups.Metadata) (*RoleOutputDTO, error) {
+		if err := a.Revoke(input.Account, input.Role); err != nil {
+			return nil, err
+		}
+		return &RoleOutputDTO{Account: input.Account, Role: input.Role, Granted: false}, nil
+	}, a.Require(RoleAdmin))
+	r.HandleInspect("roles", a.rolesHandler)
+}
+
+func (a *ACL) rolesHandler(ctx context.Context, payload []byte) error {
+	assignments, err := a.store.FindRoleAssignments()
+	if err != nil {
+		return err
+	}
+	if assignments == nil {
+		assignments = []*Assignment{}
+	}
+	body, err := json.Marshal(assignments)
+	if err != nil {
+		return err
+	}
+	return rollups.ClientFromContext(ctx).Report(ctx, body)
+}
+
+// SortAssignments orders assignments by account and then role, the order
+// Store implementations are expected to return.
+func SortAssignments(assignments []*Assignment) {
+	slices.SortFunc(assignments, func(a, b *Assignment) int {
+		if c := a.Account.Cmp(b.Account); c != 0 {
+			return c
+		}
+		switch {
+		case a.Role < b.Role:
+			return -1
+		case a.Role > b.Role:
+			return 1
+		}
+		return 0
+	})
+}
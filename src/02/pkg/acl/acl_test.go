@@ -0,0 +1,138 @@
+package acl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/rollupstest"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	admin    = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	operator = common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	stranger = common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+)
+
+func TestACLSuite(t *testing.T) {
+	suite.Run(t, new(ACLSuite))
+}
+
+type ACLSuite struct {
+	suite.Suite
+	acl    *ACL
+	server *rollupstest.Server
+	cancel context.CancelFunc
+	done   chan error
+	swept  int
+}
+
+func (s *ACLSuite) SetupTest() {
+	s.acl = New(NewMemoryStore())
+	s.Require().NoError(s.acl.Bootstrap(admin))
+	s.swept = 0
+
+	r := rollups.NewRouter()
+	s.acl.Register(r)
+	r.HandleAdvance("sweep", func(ctx context.Context, payload []byte, metadata rollups.Metadata) error {
+		s.swept++
+		return nil
+	}, s.acl.Require(RoleOperator, RoleAdmin))
+
+	s.server = rollupstest.NewServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan error, 1)
+	go func() {
+		client := s.server.Client()
+		s.done <- rollups.Run(ctx, r, rollups.WithClient(client))
+	}()
+}
+
+func (s *ACLSuite) TearDownTest() {
+	s.cancel()
+	s.NoError(<-s.done)
+	s.server.Close()
+}
+
+func (s *ACLSuite) advance(sender common.Address, path string, payload any) *rollupstest.Result {
+	data, err := json.Marshal(payload)
+	s.Require().NoError(err)
+	input, err := json.Marshal(rollups.Input{Path: path, Payload: data})
+	s.Require().NoError(err)
+	res, err := s.server.Advance(input, rollups.Metadata{MsgSender: sender})
+	s.Require().NoError(err)
+	return res
+}
+
+func (s *ACLSuite) TestGuard() {
+	s.Equal(rollupstest.StatusReject, s.advance(operator, "sweep", nil).Status)
+	s.Equal(rollupstest.StatusAccept, s.advance(admin, "sweep", nil).Status)
+
+	res := s.advance(admin, "grantRole", RoleInputDTO{Account: operator, Role: RoleOperator})
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Require().Len(res.Notices, 1)
+	s.JSONEq(`{"account":"0x70997970c51812dc3a010c7d01b50e0d17dc79c8","role":"operator","granted":true}`, string(res.Notices[0]))
+	s.Equal(rollupstest.StatusAccept, s.advance(operator, "sweep", nil).Status)
+
+	s.Equal(rollupstest.StatusAccept, s.advance(admin, "revokeRole", RoleInputDTO{Account: operator, Role: RoleOperator}).Status)
+	s.Equal(rollupstest.StatusReject, s.advance(operator, "sweep", nil).Status)
+	s.Equal(2, s.swept)
+}
+
+func (s *ACLSuite) TestOnlyAdminsGrant() {
+	res := s.advance(stranger, "grantRole", RoleInputDTO{Account: stranger, Role: RoleAdmin})
+	s.Equal(rollupstest.StatusReject, res.Status)
+	ok, err := s.acl.HasRole(stranger, RoleAdmin)
+	s.NoError(err)
+	s.False(ok)
+}
+
+func (s *ACLSuite) TestUnknownRole() {
+	res := s.advance(admin, "grantRole", RoleInputDTO{Account: operator, Role: "root"})
+	s.Equal(rollupstest.StatusReject, res.Status)
+	s.True(errors.Is(s.acl.Grant(operator, "root"), ErrUnknownRole))
+}
+
+func (s *ACLSuite) TestLastAdmin() {
+	s.True(errors.Is(s.acl.Revoke(admin, RoleAdmin), ErrLastAdmin))
+	s.NoError(s.acl.Grant(operator, RoleAdmin))
+	s.NoError(s.acl.Revoke(admin, RoleAdmin))
+}
+
+func (s *ACLSuite) TestRolesInspect() {
+	s.NoError(s.acl.Grant(operator, RoleUser))
+	s.NoError(s.acl.Grant(operator, RoleOperator))
+	res, err := s.server.Inspect([]byte("roles"))
+	s.Require().NoError(err)
+	s.Require().Len(res.Reports, 1)
+	s.JSONEq(`[
+		{"account":"0x70997970c51812dc3a010c7d01b50e0d17dc79c8","role":"operator"},
+		{"account":"0x70997970c51812dc3a010c7d01b50e0d17dc79c8","role":"user"},
+		{"account":"0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266","role":"admin"}
+	]`, string(res.Reports[0]))
+}
+
+func (s *ACLSuite) TestBootstrapOnlyEmptyStore() {
+	s.Equal(rollupstest.StatusAccept, s.advance(admin, "grantRole", RoleInputDTO{Account: operator, Role: RoleAdmin}).Status)
+	s.Equal(rollupstest.StatusAccept, s.advance(operator, "revokeRole", RoleInputDTO{Account: admin, Role: RoleAdmin}).Status)
+
+	// A restart with the same configuration must not undo the revoke.
+	s.Require().NoError(s.acl.Bootstrap(admin))
+	ok, err := s.acl.HasRole(admin, RoleAdmin)
+	s.Require().NoError(err)
+	s.False(ok)
+}
+
+func (s *ACLSuite) TestParseAccounts() {
+	accounts, err := ParseAccounts(admin.Hex() + ", " + operator.Hex())
+	s.Require().NoError(err)
+	s.Equal([]common.Address{admin, operator}, accounts)
+
+	_, err = ParseAccounts(admin.Hex() + ",0x1234")
+	s.ErrorContains(err, `invalid account "0x1234"`)
+}
@@ -0,0 +1,54 @@
+package acl
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type assignmentKey struct {
+	account common.Address
+	role    Role
+}
+
+// MemoryStore is a Store kept in process memory.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	roles map[assignmentKey]struct{}
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{roles: make(map[assignmentKey]struct{})}
+}
+
+func (s *MemoryStore) HasRole(account common.Address, role Role) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.roles[assignmentKey{account, role}]
+	return ok, nil
+}
+
+func (s *MemoryStore) GrantRole(account common.Address, role Role) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.roles[assignmentKey{account, role}] = struct{}{}
+	return nil
+}
+
+func (s *MemoryStore) RevokeRole(account common.Address, role Role) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.roles, assignmentKey{account, role})
+	return nil
+}
+
+func (s *MemoryStore) FindRoleAssignments() ([]*Assignment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	assignments := make([]*Assignment, 0, len(s.roles))
+	for key := range s.roles {
+		assignments = append(assignments, &Assignment{Account: key.account, Role: key.role})
+	}
+	SortAssignments(assignments)
+	return assignments, nil
+}
@@ -0,0 +1,84 @@
+package rollups
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+type ABIHandlerFunc func(ctx context.Context, args []any, metadata Metadata) error
+
+type abiRoute struct {
+	method  abi.Method
+	handler AdvanceHandlerFunc
+}
+
+// ParseSignature builds an abi.Method from a Solidity-style signature such
+// as "createToDo(string,string)". Tuple arguments are not supported.
+func ParseSignature(signature string) (abi.Method, error) {
+	signature = strings.ReplaceAll(signature, " ", "")
+	open := strings.Index(signature, "(")
+	if open <= 0 || !strings.HasSuffix(signature, ")") {
+		return abi.Method{}, fmt.Errorf("invalid signature: %q", signature)
+	}
+	name := signature[:open]
+	params := signature[open+1 : len(signature)-1]
+
+	var inputs abi.Arguments
+	if params != "" {
+		for _, param := range strings.Split(params, ",") {
+			if strings.ContainsAny(param, "()") {
+				return abi.Method{}, fmt.Errorf("invalid signature: %q: tuple arguments are not supported", signature)
+			}
+			typ, err := abi.NewType(param, "", nil)
+			if err != nil {
+				return abi.Method{}, fmt.Errorf("invalid signature: %q: %w", signature, err)
+			}
+			inputs = append(inputs, abi.Argument{Type: typ})
+		}
+	}
+	return abi.NewMethod(name, name, abi.Function, "", false, false, inputs, nil), nil
+}
+
+// HandleABI registers handler for ABI-encoded calldata whose 4-byte
+// selector matches signature, e.g. "createToDo(string,string)". The
+// arguments are unpacked with go-ethereum's abi package, so handlers receive
+// string, *big.Int, common.Address and so on. It panics on an invalid
+// signature or a selector that is already registered.
+func (r *Router) HandleABI(signature string, handler ABIHandlerFunc, middlewares ...Middleware) {
+	method, err := ParseSignature(signature)
+	if err != nil {
+		panic("rollups: " + err.Error())
+	}
+	var selector [4]byte
+	copy(selector[:], method.ID)
+	if route, ok := r.abiRoutes[selector]; ok {
+		panic(fmt.Sprintf("rollups: selector 0x%x of %s already registered by %s", selector, method.Sig, route.method.Sig))
+	}
+	r.abiRoutes[selector] = abiRoute{
+		method: method,
+		handler: chain(func(ctx context.Context, payload []byte, metadata Metadata) error {
+			args, err := method.Inputs.Unpack(payload[4:])
+			if err != nil {
+				return fmt.Errorf("%w: %s: %w", ErrInvalidPayload, method.Sig, err)
+			}
+			return handler(ctx, args, metadata)
+		}, middlewares),
+	}
+}
+
+func (g *Group) HandleABI(signature string, handler ABIHandlerFunc, middlewares ...Middleware) {
+	g.router.HandleABI(signature, handler, append(append([]Middleware{}, g.middlewares...), middlewares...)...)
+}
+
+func (r *Router) matchABI(payload []byte) (abiRoute, bool) {
+	if len(payload) < 4 {
+		return abiRoute{}, false
+	}
+	var selector [4]byte
+	copy(selector[:], payload[:4])
+	route, ok := r.abiRoutes[selector]
+	return route, ok
+}
@@ -0,0 +1,134 @@
+package rollups
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(ClientSuite))
+}
+
+type ClientSuite struct {
+	suite.Suite
+	calls  atomic.Int32
+	path   string
+	status []int
+	server *httptest.Server
+}
+
+func (s *ClientSuite) SetupTest() {
+	s.calls.Store(0)
+	s.path = "/notice"
+	s.status = []int{http.StatusCreated}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(s.calls.Add(1)) - 1
+		s.Equal(s.path, r.URL.Path)
+		var notice NoticeRequest
+		s.NoError(json.NewDecoder(r.Body).Decode(&notice))
+		s.Equal("0xdeadbeef", notice.Payload)
+		status := s.status[min(call, len(s.status)-1)]
+		w.WriteHeader(status)
+		if status == http.StatusCreated {
+			w.Write([]byte(`{"index": 7}`))
+		} else {
+			w.Write([]byte("server unavailable"))
+		}
+	}))
+}
+
+func (s *ClientSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *ClientSuite) TestSendNoticeUsesBaseURL() {
+	client := NewClient(WithBaseURL(s.server.URL + "/"))
+	index, err := client.SendNotice(context.Background(), &NoticeRequest{Payload: "0xdeadbeef"})
+	s.NoError(err)
+	s.Equal(uint64(7), index)
+	s.Equal(int32(1), s.calls.Load())
+}
+
+func (s *ClientSuite) TestRetryOnServerError() {
+	s.path = "/report"
+	s.status = []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusCreated}
+	client := NewClient(
+		WithBaseURL(s.server.URL),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}),
+	)
+	err := client.SendReport(context.Background(), &ReportRequest{Payload: "0xdeadbeef"})
+	s.NoError(err)
+	s.Equal(int32(3), s.calls.Load())
+}
+
+// The server may record an output and still fail the request, so retrying
+// a notice could emit it twice.
+func (s *ClientSuite) TestNoRetryForOutputs() {
+	var notices []NoticeRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notice NoticeRequest
+		s.NoError(json.NewDecoder(r.Body).Decode(&notice))
+		notices = append(notices, notice)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}),
+	)
+	_, err := client.SendNotice(context.Background(), &NoticeRequest{Payload: "0xdeadbeef"})
+	var httpErr *HTTPError
+	s.Require().True(errors.As(err, &httpErr))
+	s.Equal(http.StatusInternalServerError, httpErr.StatusCode)
+	s.Equal([]NoticeRequest{{Payload: "0xdeadbeef"}}, notices)
+}
+
+// A finish the server handled but failed to answer must not be repeated,
+// or it would settle the next request unprocessed.
+func (s *ClientSuite) TestNoRetryForFinish() {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}),
+	)
+	res, err := client.SendFinish(context.Background(), &FinishRequest{Status: "accept"})
+	if err == nil {
+		res.Body.Close()
+	}
+	s.Equal(1, calls)
+}
+
+func (s *ClientSuite) TestHTTPErrorWithoutRetry() {
+	s.status = []int{http.StatusServiceUnavailable, http.StatusCreated}
+	client := NewClient(WithBaseURL(s.server.URL))
+	_, err := client.SendNotice(context.Background(), &NoticeRequest{Payload: "0xdeadbeef"})
+	var httpErr *HTTPError
+	s.True(errors.As(err, &httpErr))
+	s.Equal("notice", httpErr.Endpoint)
+	s.Equal(http.StatusServiceUnavailable, httpErr.StatusCode)
+	s.Equal("server unavailable", httpErr.Body)
+	s.Equal(int32(1), s.calls.Load())
+}
+
+func (s *ClientSuite) TestTimeout() {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer slow.Close()
+	client := NewClient(WithBaseURL(slow.URL), WithTimeout(10*time.Millisecond))
+	_, err := client.SendNotice(context.Background(), &NoticeRequest{Payload: "0xdeadbeef"})
+	s.Error(err)
+}
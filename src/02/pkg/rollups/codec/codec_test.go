@@ -0,0 +1,55 @@
+package codec
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCodecSuite(t *testing.T) {
+	suite.Run(t, new(CodecSuite))
+}
+
+type CodecSuite struct {
+	suite.Suite
+}
+
+func (s *CodecSuite) TestRoundTrip() {
+	for _, payload := range [][]byte{{}, {0x00}, {0xde, 0xad, 0xbe, 0xef}, []byte("hello"), {0xff, 0x00, 0x80}} {
+		decoded, err := DecodeHex(EncodeHex(payload))
+		s.NoError(err)
+		s.Equal(payload, decoded)
+	}
+	s.Equal("0x", EncodeHex(nil))
+	s.Equal("0x00ff", EncodeHex([]byte{0x00, 0xff}))
+}
+
+func (s *CodecSuite) TestDecodeAcceptsUppercase() {
+	decoded, err := DecodeHex("0XDEADbeef")
+	s.NoError(err)
+	s.Equal([]byte{0xde, 0xad, 0xbe, 0xef}, decoded)
+}
+
+func (s *CodecSuite) TestDecodeErrors() {
+	for _, tc := range []struct {
+		input  string
+		err    error
+		offset int
+	}{
+		{"", ErrMissingPrefix, -1},
+		{"0", ErrMissingPrefix, -1},
+		{"deadbeef", ErrMissingPrefix, -1},
+		{"1xdead", ErrMissingPrefix, -1},
+		{"0xabc", ErrOddLength, -1},
+		{"0xzz", ErrInvalidDigit, 2},
+		{"0xabcg", ErrInvalidDigit, 5},
+	} {
+		_, err := DecodeHex(tc.input)
+		s.True(errors.Is(err, tc.err), "%q: %v", tc.input, err)
+		var hexErr *HexError
+		s.Require().True(errors.As(err, &hexErr))
+		s.Equal(tc.input, hexErr.Input)
+		s.Equal(tc.offset, hexErr.Offset, tc.input)
+	}
+}
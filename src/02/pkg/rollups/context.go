@@ -0,0 +1,68 @@
+package rollups
+
+import "context"
+
+type contextKey int
+
+const (
+	clientKey contextKey = iota
+	pathParamsKey
+	routeKey
+	depositKey
+	relayerKey
+	recorderKey
+)
+
+// NewContext returns a copy of ctx carrying client. Run attaches its client
+// this way so routers and handlers emit outputs to the same rollup server.
+func NewContext(ctx context.Context, client *Client) context.Context {
+	return context.WithValue(ctx, clientKey, client)
+}
+
+// ClientFromContext returns the client attached by NewContext, falling back
+// to the default client.
+func ClientFromContext(ctx context.Context) *Client {
+	if client, ok := ctx.Value(clientKey).(*Client); ok {
+		return client
+	}
+	return defaultClient
+}
+
+// PathParam returns the value of a {name} segment matched by the router, or
+// an empty string when the route has no such segment.
+func PathParam(ctx context.Context, name string) string {
+	params, _ := ctx.Value(pathParamsKey).(map[string]string)
+	return params[name]
+}
+
+func withPathParams(ctx context.Context, params map[string]string) context.Context {
+	if len(params) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, pathParamsKey, params)
+}
+
+// RouteFromContext returns the path the router dispatched the current input
+// to.
+func RouteFromContext(ctx context.Context) string {
+	route, _ := ctx.Value(routeKey).(string)
+	return route
+}
+
+func withRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey, route)
+}
+
+// withRecorder attaches the recorder of a Run, so that outputs sent with the
+// context are recorded whichever client sends them.
+func withRecorder(ctx context.Context, rec *recorder) context.Context {
+	if rec == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, recorderKey, rec)
+}
+
+func recorderFromContext(ctx context.Context) *recorder {
+	rec, _ := ctx.Value(recorderKey).(*recorder)
+	return rec
+}
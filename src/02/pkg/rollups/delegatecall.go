@@ -0,0 +1,88 @@
+package rollups
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// The builders below encode calls to the delegatecall helper contracts under
+// src/05/contracts/src/delegatecall. Each takes the address the helper was
+// deployed at, since it differs between networks.
+
+var (
+	safeTransferMethod           = mustParseSignature("safeTransfer(address,address,uint256)")
+	safeTransferTargetedMethod   = mustParseSignature("safeTransferTargeted(address,address,address,uint256)")
+	safeMintMethod               = mustParseSignature("safeMint(address,address,string)")
+	emergencyERC20WithdrawMethod = mustParseSignature("emergencyERC20Withdraw(address,address)")
+	emergencyETHWithdrawMethod   = mustParseSignature("emergencyETHWithdraw(address)")
+)
+
+// NewSafeERC20TransferVoucher calls SafeERC20Transfer.safeTransfer, moving
+// value of token from the application to to.
+func NewSafeERC20TransferVoucher(contract common.Address, token common.Address, to common.Address, value *big.Int) (*DelegateCallVoucherRequest, error) {
+	if err := checkUint256(value); err != nil {
+		return nil, err
+	}
+	return newDelegateCallVoucher(contract, safeTransferMethod, token, to, value)
+}
+
+// NewSafeERC20TransferTargetedVoucher calls
+// SafeERC20Transfer.safeTransferTargeted, which reverts unless the voucher is
+// executed by target.
+func NewSafeERC20TransferTargetedVoucher(contract common.Address, token common.Address, target common.Address, to common.Address, value *big.Int) (*DelegateCallVoucherRequest, error) {
+	if err := checkUint256(value); err != nil {
+		return nil, err
+	}
+	return newDelegateCallVoucher(contract, safeTransferTargetedMethod, token, target, to, value)
+}
+
+// NewSafeERC721MintVoucher calls SafeERC721Mint.safeMint, minting a token
+// with uri to to on an NFT owned by the application.
+func NewSafeERC721MintVoucher(contract common.Address, nft common.Address, to common.Address, uri string) (*DelegateCallVoucherRequest, error) {
+	return newDelegateCallVoucher(contract, safeMintMethod, nft, to, uri)
+}
+
+// NewEmergencyERC20WithdrawVoucher calls
+// EmergencyWithdraw.emergencyERC20Withdraw, sending the application's whole
+// balance of token to to.
+func NewEmergencyERC20WithdrawVoucher(contract common.Address, token common.Address, to common.Address) (*DelegateCallVoucherRequest, error) {
+	return newDelegateCallVoucher(contract, emergencyERC20WithdrawMethod, token, to)
+}
+
+// NewEmergencyETHWithdrawVoucher calls EmergencyWithdraw.emergencyETHWithdraw,
+// sending the application's whole Ether balance to to.
+func NewEmergencyETHWithdrawVoucher(contract common.Address, to common.Address) (*DelegateCallVoucherRequest, error) {
+	return newDelegateCallVoucher(contract, emergencyETHWithdrawMethod, to)
+}
+
+func newDelegateCallVoucher(contract common.Address, method abi.Method, args ...any) (*DelegateCallVoucherRequest, error) {
+	data, err := method.Inputs.Pack(args...)
+	if err != nil {
+		return nil, fmt.Errorf("rollups: failed to pack %s: %w", method.Sig, err)
+	}
+	return &DelegateCallVoucherRequest{
+		Destination: contract.Hex(),
+		Payload:     hexutil.Encode(append(append([]byte{}, method.ID...), data...)),
+	}, nil
+}
+
+// checkUint256 rejects values the abi package would panic on or silently
+// wrap around.
+func checkUint256(value *big.Int) error {
+	if value == nil || value.Sign() < 0 || value.BitLen() > 256 {
+		return fmt.Errorf("rollups: invalid uint256 value: %v", value)
+	}
+	return nil
+}
+
+func mustParseSignature(signature string) abi.Method {
+	method, err := ParseSignature(signature)
+	if err != nil {
+		panic("rollups: " + err.Error())
+	}
+	return method
+}
@@ -0,0 +1,104 @@
+package rollups
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	safeERC20TransferContract = common.HexToAddress("0x86E244fbb3243f19492A3d61336e285bbf8E6154")
+	safeERC721MintContract    = common.HexToAddress("0x4F85347240488E62ab1C6169Cbc532A09223efa4")
+	emergencyWithdrawContract = common.HexToAddress("0xA716b0bE3a59b05A307b98c6bAf9d21dF796F37d")
+)
+
+func TestDelegateCallSuite(t *testing.T) {
+	suite.Run(t, new(DelegateCallSuite))
+}
+
+type DelegateCallSuite struct {
+	suite.Suite
+}
+
+// unpack checks the destination and selector of voucher and returns its
+// decoded arguments.
+func (s *DelegateCallSuite) unpack(voucher *DelegateCallVoucherRequest, contract common.Address, signature string) []any {
+	s.Equal(contract.Hex(), voucher.Destination)
+	payload, err := hexutil.Decode(voucher.Payload)
+	s.Require().NoError(err)
+	s.Require().GreaterOrEqual(len(payload), 4)
+	s.Equal(crypto.Keccak256([]byte(signature))[:4], payload[:4])
+
+	method, err := ParseSignature(signature)
+	s.Require().NoError(err)
+	args, err := method.Inputs.Unpack(payload[4:])
+	s.Require().NoError(err)
+	return args
+}
+
+func (s *DelegateCallSuite) TestSafeERC20Transfer() {
+	voucher, err := NewSafeERC20TransferVoucher(safeERC20TransferContract, token, depositor, big.NewInt(500))
+	s.Require().NoError(err)
+	args := s.unpack(voucher, safeERC20TransferContract, "safeTransfer(address,address,uint256)")
+	s.Equal([]any{token, depositor, big.NewInt(500)}, args)
+}
+
+func (s *DelegateCallSuite) TestSafeERC20TransferTargeted() {
+	target := common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+	voucher, err := NewSafeERC20TransferTargetedVoucher(safeERC20TransferContract, token, target, depositor, big.NewInt(1))
+	s.Require().NoError(err)
+	args := s.unpack(voucher, safeERC20TransferContract, "safeTransferTargeted(address,address,address,uint256)")
+	s.Equal([]any{token, target, depositor, big.NewInt(1)}, args)
+}
+
+func (s *DelegateCallSuite) TestSafeERC721Mint() {
+	voucher, err := NewSafeERC721MintVoucher(safeERC721MintContract, token, depositor, "ipfs://token")
+	s.Require().NoError(err)
+	args := s.unpack(voucher, safeERC721MintContract, "safeMint(address,address,string)")
+	s.Equal([]any{token, depositor, "ipfs://token"}, args)
+}
+
+func (s *DelegateCallSuite) TestEmergencyWithdraw() {
+	voucher, err := NewEmergencyERC20WithdrawVoucher(emergencyWithdrawContract, token, depositor)
+	s.Require().NoError(err)
+	args := s.unpack(voucher, emergencyWithdrawContract, "emergencyERC20Withdraw(address,address)")
+	s.Equal([]any{token, depositor}, args)
+
+	voucher, err = NewEmergencyETHWithdrawVoucher(emergencyWithdrawContract, depositor)
+	s.Require().NoError(err)
+	args = s.unpack(voucher, emergencyWithdrawContract, "emergencyETHWithdraw(address)")
+	s.Equal([]any{depositor}, args)
+}
+
+func (s *DelegateCallSuite) TestInvalidArguments() {
+	_, err := NewSafeERC20TransferVoucher(safeERC20TransferContract, token, depositor, nil)
+	s.Error(err)
+	_, err = NewSafeERC20TransferTargetedVoucher(safeERC20TransferContract, token, depositor, depositor, big.NewInt(-1))
+	s.Error(err)
+}
+
+func (s *DelegateCallSuite) TestSendDelegateCallVoucher() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("/delegate-call-voucher", r.URL.Path)
+		var voucher DelegateCallVoucherRequest
+		s.NoError(json.NewDecoder(r.Body).Decode(&voucher))
+		s.Equal(emergencyWithdrawContract.Hex(), voucher.Destination)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"index": 3}`))
+	}))
+	defer server.Close()
+
+	voucher, err := NewEmergencyETHWithdrawVoucher(emergencyWithdrawContract, depositor)
+	s.Require().NoError(err)
+	index, err := NewClient(WithBaseURL(server.URL)).SendDelegateCallVoucher(context.Background(), voucher)
+	s.NoError(err)
+	s.Equal(uint64(3), index)
+}
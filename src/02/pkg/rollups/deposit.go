@@ -0,0 +1,214 @@
+package rollups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrMalformedDeposit = errors.New("malformed deposit")
+
+// PortalConfig holds the addresses of the portals the application accepts
+// deposits from. Inputs whose msg_sender matches one of them are decoded
+// as deposits instead of being routed as regular inputs.
+type PortalConfig struct {
+	EtherPortal         common.Address
+	ERC20Portal         common.Address
+	ERC721Portal        common.Address
+	ERC1155SinglePortal common.Address
+	ERC1155BatchPortal  common.Address
+}
+
+// DefaultPortalConfig returns the portal addresses of the Cartesi Rollups v2
+// deployment used by the cartesi cli devnet.
+func DefaultPortalConfig() PortalConfig {
+	return PortalConfig{
+		EtherPortal:         common.HexToAddress("0xc70076a466789B595b50959cdc261227F0D70051"),
+		ERC20Portal:         common.HexToAddress("0xc700D6aDd016eECd59d989C028214Eaa0fCC0051"),
+		ERC721Portal:        common.HexToAddress("0xc700d52F5290e978e9CAe7D1E092935263b60051"),
+		ERC1155SinglePortal: common.HexToAddress("0xc700A261279aFC6F755A3a67D86ae43E2eBD0051"),
+		ERC1155BatchPortal:  common.HexToAddress("0xc700A2e5531E720a2434433b6ccf4c0eA2400051"),
+	}
+}
+
+// Deposit is implemented by every decoded portal input.
+type Deposit interface {
+	Depositor() common.Address
+}
+
+type EtherDeposit struct {
+	Sender common.Address `json:"sender"`
+	Value  *big.Int       `json:"value"`
+}
+
+type ERC20Deposit struct {
+	Token  common.Address `json:"token"`
+	Sender common.Address `json:"sender"`
+	Value  *big.Int       `json:"value"`
+}
+
+type ERC721Deposit struct {
+	Token         common.Address `json:"token"`
+	Sender        common.Address `json:"sender"`
+	TokenId       *big.Int       `json:"token_id"`
+	BaseLayerData []byte         `json:"base_layer_data"`
+}
+
+type ERC1155SingleDeposit struct {
+	Token         common.Address `json:"token"`
+	Sender        common.Address `json:"sender"`
+	TokenId       *big.Int       `json:"token_id"`
+	Value         *big.Int       `json:"value"`
+	BaseLayerData []byte         `json:"base_layer_data"`
+}
+
+type ERC1155BatchDeposit struct {
+	Token         common.Address `json:"token"`
+	Sender        common.Address `json:"sender"`
+	TokenIds      []*big.Int     `json:"token_ids"`
+	Values        []*big.Int     `json:"values"`
+	BaseLayerData []byte         `json:"base_layer_data"`
+}
+
+func (d *EtherDeposit) Depositor() common.Address         { return d.Sender }
+func (d *ERC20Deposit) Depositor() common.Address         { return d.Sender }
+func (d *ERC721Deposit) Depositor() common.Address        { return d.Sender }
+func (d *ERC1155SingleDeposit) Depositor() common.Address { return d.Sender }
+func (d *ERC1155BatchDeposit) Depositor() common.Address  { return d.Sender }
+
+var (
+	uint256ArrayType, _ = abi.NewType("uint256[]", "", nil)
+	bytesType, _        = abi.NewType("bytes", "", nil)
+
+	// abi.encode(baseLayerData, execLayerData)
+	layerDataArgs = abi.Arguments{{Type: bytesType}, {Type: bytesType}}
+	// abi.encode(tokenIds, values, baseLayerData, execLayerData)
+	batchDataArgs = abi.Arguments{{Type: uint256ArrayType}, {Type: uint256ArrayType}, {Type: bytesType}, {Type: bytesType}}
+)
+
+// IsPortal reports whether sender is one of the configured portals.
+func (c PortalConfig) IsPortal(sender common.Address) bool {
+	switch sender {
+	case c.EtherPortal, c.ERC20Portal, c.ERC721Portal, c.ERC1155SinglePortal, c.ERC1155BatchPortal:
+		return sender != common.Address{}
+	}
+	return false
+}
+
+// DecodeDeposit parses the packed payload sent by the portal at sender and
+// returns the deposit together with the execLayerData the depositor attached
+// to it. It fails if sender is not a configured portal.
+func (c PortalConfig) DecodeDeposit(sender common.Address, payload []byte) (Deposit, []byte, error) {
+	if !c.IsPortal(sender) {
+		return nil, nil, fmt.Errorf("%s is not a portal", sender)
+	}
+	switch sender {
+	case c.EtherPortal:
+		return decodeEtherDeposit(payload)
+	case c.ERC20Portal:
+		return decodeERC20Deposit(payload)
+	case c.ERC721Portal:
+		return decodeERC721Deposit(payload)
+	case c.ERC1155SinglePortal:
+		return decodeERC1155SingleDeposit(payload)
+	default:
+		return decodeERC1155BatchDeposit(payload)
+	}
+}
+
+// abi.encodePacked(sender, value, execLayerData)
+func decodeEtherDeposit(payload []byte) (Deposit, []byte, error) {
+	if len(payload) < 20+32 {
+		return nil, nil, fmt.Errorf("%w: ether deposit too short: %d bytes", ErrMalformedDeposit, len(payload))
+	}
+	return &EtherDeposit{
+		Sender: common.BytesToAddress(payload[:20]),
+		Value:  new(big.Int).SetBytes(payload[20:52]),
+	}, payload[52:], nil
+}
+
+// abi.encodePacked(token, sender, value, execLayerData)
+func decodeERC20Deposit(payload []byte) (Deposit, []byte, error) {
+	if len(payload) < 20+20+32 {
+		return nil, nil, fmt.Errorf("%w: erc20 deposit too short: %d bytes", ErrMalformedDeposit, len(payload))
+	}
+	return &ERC20Deposit{
+		Token:  common.BytesToAddress(payload[:20]),
+		Sender: common.BytesToAddress(payload[20:40]),
+		Value:  new(big.Int).SetBytes(payload[40:72]),
+	}, payload[72:], nil
+}
+
+// abi.encodePacked(token, sender, tokenId, abi.encode(baseLayerData, execLayerData))
+func decodeERC721Deposit(payload []byte) (Deposit, []byte, error) {
+	if len(payload) < 20+20+32 {
+		return nil, nil, fmt.Errorf("%w: erc721 deposit too short: %d bytes", ErrMalformedDeposit, len(payload))
+	}
+	data, err := layerDataArgs.Unpack(payload[72:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: erc721 deposit: %w", ErrMalformedDeposit, err)
+	}
+	return &ERC721Deposit{
+		Token:         common.BytesToAddress(payload[:20]),
+		Sender:        common.BytesToAddress(payload[20:40]),
+		TokenId:       new(big.Int).SetBytes(payload[40:72]),
+		BaseLayerData: data[0].([]byte),
+	}, data[1].([]byte), nil
+}
+
+// abi.encodePacked(token, sender, tokenId, value, abi.encode(baseLayerData, execLayerData))
+func decodeERC1155SingleDeposit(payload []byte) (Deposit, []byte, error) {
+	if len(payload) < 20+20+32+32 {
+		return nil, nil, fmt.Errorf("%w: erc1155 deposit too short: %d bytes", ErrMalformedDeposit, len(payload))
+	}
+	data, err := layerDataArgs.Unpack(payload[104:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: erc1155 deposit: %w", ErrMalformedDeposit, err)
+	}
+	return &ERC1155SingleDeposit{
+		Token:         common.BytesToAddress(payload[:20]),
+		Sender:        common.BytesToAddress(payload[20:40]),
+		TokenId:       new(big.Int).SetBytes(payload[40:72]),
+		Value:         new(big.Int).SetBytes(payload[72:104]),
+		BaseLayerData: data[0].([]byte),
+	}, data[1].([]byte), nil
+}
+
+// abi.encodePacked(token, sender, abi.encode(tokenIds, values, baseLayerData, execLayerData))
+func decodeERC1155BatchDeposit(payload []byte) (Deposit, []byte, error) {
+	if len(payload) < 20+20 {
+		return nil, nil, fmt.Errorf("%w: erc1155 batch deposit too short: %d bytes", ErrMalformedDeposit, len(payload))
+	}
+	data, err := batchDataArgs.Unpack(payload[40:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: erc1155 batch deposit: %w", ErrMalformedDeposit, err)
+	}
+	tokenIds, values := data[0].([]*big.Int), data[1].([]*big.Int)
+	if len(tokenIds) != len(values) {
+		return nil, nil, fmt.Errorf("%w: erc1155 batch deposit: %d token ids for %d values", ErrMalformedDeposit, len(tokenIds), len(values))
+	}
+	return &ERC1155BatchDeposit{
+		Token:         common.BytesToAddress(payload[:20]),
+		Sender:        common.BytesToAddress(payload[20:40]),
+		TokenIds:      tokenIds,
+		Values:        values,
+		BaseLayerData: data[2].([]byte),
+	}, data[3].([]byte), nil
+}
+
+type DepositHandlerFunc func(ctx context.Context, deposit Deposit, metadata Metadata) error
+
+// DepositFromContext returns the deposit that carried the current input as
+// execLayerData, if any.
+func DepositFromContext(ctx context.Context) (Deposit, bool) {
+	deposit, ok := ctx.Value(depositKey).(Deposit)
+	return deposit, ok
+}
+
+func withDeposit(ctx context.Context, deposit Deposit) context.Context {
+	return context.WithValue(ctx, depositKey, deposit)
+}
@@ -0,0 +1,175 @@
+package rollups
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/codec"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	depositor = common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	token     = common.HexToAddress("0xFBdB734EF6a23aD76863CbA6f10d0C5CBBD8342C")
+)
+
+func TestDepositSuite(t *testing.T) {
+	suite.Run(t, new(DepositSuite))
+}
+
+type DepositSuite struct {
+	suite.Suite
+	portals PortalConfig
+}
+
+func (s *DepositSuite) SetupTest() {
+	s.portals = DefaultPortalConfig()
+}
+
+func packed(parts ...[]byte) []byte {
+	var out []byte
+	for _, part := range parts {
+		out = append(out, part...)
+	}
+	return out
+}
+
+func word(v int64) []byte {
+	return big.NewInt(v).FillBytes(make([]byte, 32))
+}
+
+func (s *DepositSuite) TestEtherDeposit() {
+	payload := packed(depositor[:], word(100), []byte("exec"))
+	deposit, exec, err := s.portals.DecodeDeposit(s.portals.EtherPortal, payload)
+	s.Require().NoError(err)
+	s.Equal(&EtherDeposit{Sender: depositor, Value: big.NewInt(100)}, deposit)
+	s.Equal([]byte("exec"), exec)
+}
+
+func (s *DepositSuite) TestERC20Deposit() {
+	payload := packed(token[:], depositor[:], word(10000))
+	deposit, exec, err := s.portals.DecodeDeposit(s.portals.ERC20Portal, payload)
+	s.Require().NoError(err)
+	s.Equal(&ERC20Deposit{Token: token, Sender: depositor, Value: big.NewInt(10000)}, deposit)
+	s.Empty(exec)
+}
+
+func (s *DepositSuite) TestERC721Deposit() {
+	data, err := layerDataArgs.Pack([]byte("base"), []byte("exec"))
+	s.Require().NoError(err)
+	payload := packed(token[:], depositor[:], word(7), data)
+	deposit, exec, err := s.portals.DecodeDeposit(s.portals.ERC721Portal, payload)
+	s.Require().NoError(err)
+	s.Equal(&ERC721Deposit{Token: token, Sender: depositor, TokenId: big.NewInt(7), BaseLayerData: []byte("base")}, deposit)
+	s.Equal([]byte("exec"), exec)
+}
+
+func (s *DepositSuite) TestERC1155SingleDeposit() {
+	data, err := layerDataArgs.Pack([]byte{}, []byte{})
+	s.Require().NoError(err)
+	payload := packed(token[:], depositor[:], word(1), word(50), data)
+	deposit, exec, err := s.portals.DecodeDeposit(s.portals.ERC1155SinglePortal, payload)
+	s.Require().NoError(err)
+	s.Equal(&ERC1155SingleDeposit{Token: token, Sender: depositor, TokenId: big.NewInt(1), Value: big.NewInt(50), BaseLayerData: []byte{}}, deposit)
+	s.Empty(exec)
+}
+
+func (s *DepositSuite) TestERC1155BatchDeposit() {
+	ids := []*big.Int{big.NewInt(1), big.NewInt(2)}
+	values := []*big.Int{big.NewInt(10), big.NewInt(20)}
+	data, err := batchDataArgs.Pack(ids, values, []byte{}, []byte("exec"))
+	s.Require().NoError(err)
+	payload := packed(token[:], depositor[:], data)
+	deposit, exec, err := s.portals.DecodeDeposit(s.portals.ERC1155BatchPortal, payload)
+	s.Require().NoError(err)
+	s.Equal(&ERC1155BatchDeposit{Token: token, Sender: depositor, TokenIds: ids, Values: values, BaseLayerData: []byte{}}, deposit)
+	s.Equal([]byte("exec"), exec)
+}
+
+func (s *DepositSuite) TestMalformedDeposit() {
+	_, _, err := s.portals.DecodeDeposit(s.portals.ERC20Portal, packed(token[:], depositor[:]))
+	s.True(errors.Is(err, ErrMalformedDeposit))
+	_, _, err = s.portals.DecodeDeposit(s.portals.ERC721Portal, packed(token[:], depositor[:], word(1), []byte{0x01}))
+	s.True(errors.Is(err, ErrMalformedDeposit))
+}
+
+func (s *DepositSuite) TestRouterForwardsExecLayerData() {
+	var calls []string
+	router := NewRouter()
+	router.HandleDeposit(func(ctx context.Context, deposit Deposit, metadata Metadata) error {
+		calls = append(calls, "deposit:"+deposit.(*EtherDeposit).Value.String())
+		return nil
+	})
+	router.HandleAdvance("buy", func(ctx context.Context, payload []byte, metadata Metadata) error {
+		_, ok := DepositFromContext(ctx)
+		s.True(ok)
+		calls = append(calls, "buy:"+metadata.MsgSender.Hex())
+		return nil
+	})
+
+	payload := packed(depositor[:], word(100), []byte(`{"path":"buy"}`))
+	err := router.Advance(context.Background(), payload, Metadata{MsgSender: s.portals.EtherPortal})
+	s.NoError(err)
+	s.Equal([]string{"deposit:100", "buy:" + depositor.Hex()}, calls)
+}
+
+func (s *DepositSuite) TestRouterKeepsDepositWhenExecLayerDataFails() {
+	var reports []ReportRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("/report", r.URL.Path)
+		var report ReportRequest
+		s.NoError(json.NewDecoder(r.Body).Decode(&report))
+		reports = append(reports, report)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	ctx := NewContext(context.Background(), NewClient(WithBaseURL(server.URL)))
+
+	var deposits []string
+	router := NewRouter()
+	router.HandleDeposit(func(ctx context.Context, deposit Deposit, metadata Metadata) error {
+		deposits = append(deposits, deposit.(*EtherDeposit).Value.String())
+		return nil
+	})
+	router.HandleAdvance("buy", func(ctx context.Context, payload []byte, metadata Metadata) error {
+		return errors.New("out of stock")
+	})
+
+	for _, execLayerData := range []string{`{"path":"buy"}`, `{"path":"sell"}`, `not json`} {
+		payload := packed(depositor[:], word(100), []byte(execLayerData))
+		s.NoError(router.Advance(ctx, payload, Metadata{MsgSender: s.portals.EtherPortal}), execLayerData)
+	}
+	s.Equal([]string{"100", "100", "100"}, deposits)
+	s.Require().Len(reports, 3)
+
+	body, err := codec.DecodeHex(reports[0].Payload)
+	s.Require().NoError(err)
+	var report ErrorReport
+	s.NoError(json.Unmarshal(body, &report))
+	s.Equal("handler: deposit accepted, execLayerData failed: out of stock", report.Error)
+}
+
+func (s *DepositSuite) TestRouterStopsOnFatalExecLayerData() {
+	router := NewRouter()
+	router.HandleDeposit(func(ctx context.Context, deposit Deposit, metadata Metadata) error {
+		return nil
+	})
+	router.HandleAdvance("buy", func(ctx context.Context, payload []byte, metadata Metadata) error {
+		return Fatal(errors.New("broken invariant"))
+	})
+	payload := packed(depositor[:], word(100), []byte(`{"path":"buy"}`))
+	err := router.Advance(context.Background(), payload, Metadata{MsgSender: s.portals.EtherPortal})
+	s.True(IsFatal(err))
+}
+
+func (s *DepositSuite) TestRouterWithoutDepositHandler() {
+	payload := packed(depositor[:], word(100))
+	err := NewRouter().Advance(context.Background(), payload, Metadata{MsgSender: s.portals.EtherPortal})
+	s.ErrorContains(err, "no deposit handler")
+}
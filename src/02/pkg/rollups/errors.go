@@ -0,0 +1,21 @@
+package rollups
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// HTTPError is returned when the rollup server answers an output request with
+// a status outside the 2xx range.
+type HTTPError struct {
+	Endpoint   string
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("rollups: %s: unexpected status %d %s", e.Endpoint, e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("rollups: %s: unexpected status %d %s: %s", e.Endpoint, e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
@@ -0,0 +1,73 @@
+package rollups
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/codec"
+)
+
+// FatalError marks an error the application cannot recover from, such as
+// corrupted state or a broken invariant. Run reports it through /exception
+// and halts instead of rejecting the input.
+type FatalError struct {
+	Err error
+}
+
+func (e *FatalError) Error() string {
+	return "fatal: " + e.Err.Error()
+}
+
+func (e *FatalError) Unwrap() error {
+	return e.Err
+}
+
+// Fatal wraps err in a *FatalError. It returns nil if err is nil.
+func Fatal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &FatalError{Err: err}
+}
+
+// Fatalf is Fatal(fmt.Errorf(format, args...)).
+func Fatalf(format string, args ...any) error {
+	return &FatalError{Err: fmt.Errorf(format, args...)}
+}
+
+// IsFatal reports whether err, or any error it wraps, is a *FatalError.
+func IsFatal(err error) bool {
+	var fatal *FatalError
+	return errors.As(err, &fatal)
+}
+
+// Exception is the JSON payload Run sends to /exception.
+type Exception struct {
+	Error       string  `json:"error"`
+	RequestType string  `json:"request_type,omitempty"`
+	InputIndex  *uint64 `json:"input_index,omitempty"`
+}
+
+// throw reports err through /exception. response is the request that was
+// being handled, or nil if the failure happened before one was decoded. The
+// returned error is err, joined with the send error if /exception failed.
+func throw(ctx context.Context, client *Client, response *FinishResponse, err error) error {
+	exception := Exception{Error: err.Error()}
+	if response != nil {
+		exception.RequestType = response.Type
+		var data AdvanceResponse
+		if response.Type == RequestTypeAdvance && json.Unmarshal(response.Data, &data) == nil {
+			exception.InputIndex = &data.Metadata.InputIndex
+		}
+	}
+	payload, marshalErr := json.Marshal(exception)
+	if marshalErr != nil {
+		return errors.Join(err, marshalErr)
+	}
+	if sendErr := client.SendException(ctx, &ExceptionRequest{Payload: codec.EncodeHex(payload)}); sendErr != nil {
+		return errors.Join(err, sendErr)
+	}
+	return err
+}
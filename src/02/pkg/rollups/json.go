@@ -0,0 +1,105 @@
+package rollups
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	ErrInvalidPayload = errors.New("invalid payload")
+	ErrInvalidInput   = errors.New("invalid input")
+)
+
+// validate is shared by every JSON route: validator.Validate caches struct
+// metadata, so building one per input is wasteful.
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
+
+// AdvanceRegistrar is implemented by Router and Group.
+type AdvanceRegistrar interface {
+	HandleAdvance(path string, handler AdvanceHandlerFunc, middlewares ...Middleware)
+}
+
+type JSONHandlerFunc[T, Out any] func(ctx context.Context, input T, metadata Metadata) (Out, error)
+
+// HandleJSON registers a route whose payload is decoded into T and checked
+// with the `validate` struct tags before handler runs. The value handler
+// returns is emitted as a JSON notice, or with its own encoding if it is a
+// NoticeMarshaler. Decode and validation failures are
+// answered with an ErrorReport and reject the input.
+func HandleJSON[T, Out any](r AdvanceRegistrar, path string, handler JSONHandlerFunc[T, Out], middlewares ...Middleware) {
+	r.HandleAdvance(path, func(ctx context.Context, payload []byte, metadata Metadata) error {
+		input, err := decodeJSON[T](payload)
+		if err != nil {
+			if reportErr := ReportError(ctx, err); reportErr != nil {
+				return errors.Join(err, reportErr)
+			}
+			return reportedError{err}
+		}
+		output, err := handler(ctx, input, metadata)
+		if err != nil {
+			return err
+		}
+		if marshaler, ok := any(output).(NoticeMarshaler); ok {
+			_, err = SendMarshaledNotice(ctx, marshaler)
+			return err
+		}
+		_, err = SendJSONNotice(ctx, output)
+		return err
+	}, middlewares...)
+}
+
+// SendJSONNotice encodes v as JSON and emits it as a notice through the
+// client in ctx.
+func SendJSONNotice(ctx context.Context, v any) (uint64, error) {
+	notice, err := json.Marshal(v)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode notice: %w", err)
+	}
+	index, err := ClientFromContext(ctx).Notice(ctx, notice)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send notice: %w", err)
+	}
+	return index, nil
+}
+
+func decodeJSON[T any](payload []byte) (T, error) {
+	var input T
+	if err := json.Unmarshal(payload, &input); err != nil {
+		return input, fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+	if !isStruct(reflect.TypeOf(input)) {
+		return input, nil
+	}
+	if err := validate.Struct(input); err != nil {
+		return input, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
+	return input, nil
+}
+
+func isStruct(t reflect.Type) bool {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t != nil && t.Kind() == reflect.Struct
+}
@@ -0,0 +1,91 @@
+package rollups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var ErrSenderNotAllowed = errors.New("msg_sender not allowed")
+
+// Middleware wraps an AdvanceHandlerFunc with extra behaviour. Middlewares
+// registered with Router.Use run first, then group ones, then route ones.
+type Middleware func(next AdvanceHandlerFunc) AdvanceHandlerFunc
+
+func chain(handler AdvanceHandlerFunc, middlewares []Middleware) AdvanceHandlerFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// Recover turns a panic inside the handler into an error, so the input is
+// rejected instead of crashing the application.
+func Recover() Middleware {
+	return func(next AdvanceHandlerFunc) AdvanceHandlerFunc {
+		return func(ctx context.Context, payload []byte, metadata Metadata) (err error) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					err = fmt.Errorf("panic in %q handler: %v\n%s", RouteFromContext(ctx), rec, debug.Stack())
+				}
+			}()
+			return next(ctx, payload, metadata)
+		}
+	}
+}
+
+// Logger logs every advance input keyed by its input index, along with the
+// route, the sender and whether the handler failed.
+func Logger(logger *slog.Logger) Middleware {
+	return func(next AdvanceHandlerFunc) AdvanceHandlerFunc {
+		return func(ctx context.Context, payload []byte, metadata Metadata) error {
+			logger := logger.With(
+				"input_index", metadata.InputIndex,
+				"path", RouteFromContext(ctx),
+				"msg_sender", metadata.MsgSender,
+			)
+			err := next(ctx, payload, metadata)
+			if err != nil {
+				logger.Error("input rejected", "error", err)
+				return err
+			}
+			logger.Info("input accepted")
+			return nil
+		}
+	}
+}
+
+// Timing reports how long the handler of each path took to run.
+func Timing(observe func(path string, elapsed time.Duration)) Middleware {
+	return func(next AdvanceHandlerFunc) AdvanceHandlerFunc {
+		return func(ctx context.Context, payload []byte, metadata Metadata) error {
+			start := time.Now()
+			defer func() {
+				observe(RouteFromContext(ctx), time.Since(start))
+			}()
+			return next(ctx, payload, metadata)
+		}
+	}
+}
+
+// AllowSenders rejects inputs whose msg_sender is not one of senders.
+// Addresses are compared case-insensitively.
+func AllowSenders(senders ...string) Middleware {
+	allowed := make(map[common.Address]struct{}, len(senders))
+	for _, sender := range senders {
+		allowed[common.HexToAddress(sender)] = struct{}{}
+	}
+	return func(next AdvanceHandlerFunc) AdvanceHandlerFunc {
+		return func(ctx context.Context, payload []byte, metadata Metadata) error {
+			if _, ok := allowed[metadata.MsgSender]; !ok {
+				return fmt.Errorf("%w: %s", ErrSenderNotAllowed, metadata.MsgSender)
+			}
+			return next(ctx, payload, metadata)
+		}
+	}
+}
@@ -2,6 +2,10 @@ package rollups
 
 import (
 	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/codec"
 )
 
 type Input struct {
@@ -28,13 +32,40 @@ type AdvanceResponse struct {
 }
 
 type Metadata struct {
-	ChainID        uint64 `json:"chain_id"`
-	AppContract    string `json:"app_contract"`
-	MsgSender      string `json:"msg_sender"`
-	InputIndex     uint64 `json:"input_index"`
-	BlockNumber    uint64 `json:"block_number"`
-	BlockTimestamp uint64 `json:"block_timestamp"`
-	PrevRandao     string `json:"prev_randao"`
+	ChainID        uint64         `json:"chain_id"`
+	AppContract    common.Address `json:"app_contract"`
+	MsgSender      common.Address `json:"msg_sender"`
+	InputIndex     uint64         `json:"input_index"`
+	BlockNumber    uint64         `json:"block_number"`
+	BlockTimestamp uint64         `json:"block_timestamp"`
+	PrevRandao     common.Hash    `json:"prev_randao"`
+}
+
+// UnmarshalJSON decodes metadata as sent by the rollup server. prev_randao
+// is a uint256 and may come without leading zeros, or empty on chains that
+// do not provide it, so it is left-padded to 32 bytes.
+func (m *Metadata) UnmarshalJSON(data []byte) error {
+	type metadata Metadata
+	var raw struct {
+		metadata
+		PrevRandao string `json:"prev_randao"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*m = Metadata(raw.metadata)
+	if raw.PrevRandao == "" {
+		return nil
+	}
+	prevRandao, err := codec.DecodeHex(raw.PrevRandao)
+	if err != nil {
+		return fmt.Errorf("rollups: invalid prev_randao: %w", err)
+	}
+	if len(prevRandao) > common.HashLength {
+		return fmt.Errorf("rollups: invalid prev_randao: %d bytes, want at most %d", len(prevRandao), common.HashLength)
+	}
+	m.PrevRandao = common.BytesToHash(prevRandao)
+	return nil
 }
 
 type ReportRequest struct {
@@ -51,6 +82,14 @@ type VoucherRequest struct {
 	Payload     string `json:"payload"`
 }
 
+// DelegateCallVoucherRequest is executed by the application contract with
+// DELEGATECALL, so payload runs against the application's own storage and
+// balances instead of the destination's.
+type DelegateCallVoucherRequest struct {
+	Destination string `json:"destination"`
+	Payload     string `json:"payload"`
+}
+
 type ExceptionRequest struct {
 	Payload string `json:"payload"`
 }
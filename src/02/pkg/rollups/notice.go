@@ -0,0 +1,188 @@
+package rollups
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+var ErrUnknownNotice = errors.New("unknown notice")
+
+// noticeHeaderLength is the version byte followed by the 4-byte selector.
+const noticeHeaderLength = 5
+
+// NoticeSchema describes an ABI-encoded notice such as
+// "ToDoCreated(uint256,address,string,uint64)". Its payload is the schema
+// version, the selector of the signature and the ABI encoding of the
+// arguments, so a contract holding the validated notice can check
+// notice[0] and bytes4(notice[1:5]) and then
+// abi.decode(notice[5:], (uint256, address, string, uint64)).
+type NoticeSchema struct {
+	Version uint8
+	method  abi.Method
+}
+
+// NewNoticeSchema parses signature with ParseSignature.
+func NewNoticeSchema(version uint8, signature string) (*NoticeSchema, error) {
+	method, err := ParseSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	return &NoticeSchema{Version: version, method: method}, nil
+}
+
+// Signature returns the canonical signature, e.g. "ToDoDeleted(uint256)".
+func (s *NoticeSchema) Signature() string {
+	return s.method.Sig
+}
+
+func (s *NoticeSchema) Selector() [4]byte {
+	var selector [4]byte
+	copy(selector[:], s.method.ID)
+	return selector
+}
+
+// Encode packs args, given as the Go types go-ethereum's abi package
+// expects (*big.Int for uint256, common.Address, uint64...), into a notice
+// payload.
+func (s *NoticeSchema) Encode(args ...any) ([]byte, error) {
+	data, err := s.method.Inputs.Pack(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s notice: %w", s.method.Sig, err)
+	}
+	selector := s.Selector()
+	payload := make([]byte, 0, noticeHeaderLength+len(data))
+	payload = append(payload, s.Version)
+	payload = append(payload, selector[:]...)
+	return append(payload, data...), nil
+}
+
+// Decode unpacks a payload produced by Encode.
+func (s *NoticeSchema) Decode(payload []byte) ([]any, error) {
+	version, selector, ok := noticeHeader(payload)
+	if !ok || version != s.Version || selector != s.Selector() {
+		return nil, fmt.Errorf("%w: not a v%d %s notice", ErrUnknownNotice, s.Version, s.method.Sig)
+	}
+	args, err := s.method.Inputs.Unpack(payload[noticeHeaderLength:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidPayload, s.method.Sig, err)
+	}
+	return args, nil
+}
+
+// Format renders decoded args as a call, e.g.
+// ToDoCreated(1, 0x70997970C51812dc3A010C7d01b50e0d17dc79C8, "title", 1700000000).
+func (s *NoticeSchema) Format(args []any) string {
+	var b strings.Builder
+	b.WriteString(s.method.Name)
+	b.WriteByte('(')
+	for i, arg := range args {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if text, ok := arg.(string); ok {
+			b.WriteString(strconv.Quote(text))
+			continue
+		}
+		fmt.Fprint(&b, arg)
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+func noticeHeader(payload []byte) (uint8, [4]byte, bool) {
+	var selector [4]byte
+	if len(payload) < noticeHeaderLength {
+		return 0, selector, false
+	}
+	copy(selector[:], payload[1:noticeHeaderLength])
+	return payload[0], selector, true
+}
+
+type noticeKey struct {
+	version  uint8
+	selector [4]byte
+}
+
+// NoticeRegistry keeps the notice schemas of an application, so tools and
+// tests can decode any of its notices without knowing which one to expect.
+type NoticeRegistry struct {
+	mu      sync.RWMutex
+	schemas map[noticeKey]*NoticeSchema
+}
+
+func NewNoticeRegistry() *NoticeRegistry {
+	return &NoticeRegistry{schemas: make(map[noticeKey]*NoticeSchema)}
+}
+
+// Notices is the registry RegisterNotice adds to.
+var Notices = NewNoticeRegistry()
+
+// RegisterNotice adds a schema to Notices. It is meant for package-level
+// variables and panics like Register.
+func RegisterNotice(version uint8, signature string) *NoticeSchema {
+	return Notices.Register(version, signature)
+}
+
+// Register adds the schema for version and signature. It panics on an
+// invalid signature or when a schema with the same version and selector is
+// already registered.
+func (r *NoticeRegistry) Register(version uint8, signature string) *NoticeSchema {
+	schema, err := NewNoticeSchema(version, signature)
+	if err != nil {
+		panic("rollups: " + err.Error())
+	}
+	key := noticeKey{version: version, selector: schema.Selector()}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if registered, ok := r.schemas[key]; ok {
+		panic(fmt.Sprintf("rollups: v%d notice 0x%x of %s already registered by %s", version, key.selector, schema.Signature(), registered.Signature()))
+	}
+	r.schemas[key] = schema
+	return schema
+}
+
+// Decode finds the schema of payload and unpacks it.
+func (r *NoticeRegistry) Decode(payload []byte) (*NoticeSchema, []any, error) {
+	version, selector, ok := noticeHeader(payload)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: payload too short", ErrUnknownNotice)
+	}
+	r.mu.RLock()
+	schema, ok := r.schemas[noticeKey{version: version, selector: selector}]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: v%d selector 0x%x", ErrUnknownNotice, version, selector)
+	}
+	args, err := schema.Decode(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	return schema, args, nil
+}
+
+// NoticeMarshaler is implemented by handler outputs that encode their own
+// notice payload, typically with a NoticeSchema. HandleJSON sends them as
+// is instead of as JSON.
+type NoticeMarshaler interface {
+	MarshalNotice() ([]byte, error)
+}
+
+// SendMarshaledNotice emits the payload v marshals to as a notice through
+// the client in ctx.
+func SendMarshaledNotice(ctx context.Context, v NoticeMarshaler) (uint64, error) {
+	notice, err := v.MarshalNotice()
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode notice: %w", err)
+	}
+	index, err := ClientFromContext(ctx).Notice(ctx, notice)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send notice: %w", err)
+	}
+	return index, nil
+}
@@ -0,0 +1,84 @@
+package rollups
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestNoticeSuite(t *testing.T) {
+	suite.Run(t, new(NoticeSuite))
+}
+
+type NoticeSuite struct {
+	suite.Suite
+	registry *NoticeRegistry
+	created  *NoticeSchema
+}
+
+func (s *NoticeSuite) SetupTest() {
+	s.registry = NewNoticeRegistry()
+	s.created = s.registry.Register(1, "ToDoCreated(uint256, address, string, uint64)")
+}
+
+func (s *NoticeSuite) TestEncodeLayout() {
+	owner := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	payload, err := s.created.Encode(big.NewInt(7), owner, "title", uint64(1700000000))
+	s.Require().NoError(err)
+
+	s.Equal(byte(1), payload[0])
+	s.Equal(crypto.Keccak256([]byte("ToDoCreated(uint256,address,string,uint64)"))[:4], payload[1:5])
+	s.Equal(common.LeftPadBytes([]byte{7}, 32), payload[5:37])
+	s.Equal(common.LeftPadBytes(owner.Bytes(), 32), payload[37:69])
+}
+
+func (s *NoticeSuite) TestRegistryDecode() {
+	owner := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	payload, err := s.created.Encode(big.NewInt(7), owner, "title", uint64(1700000000))
+	s.Require().NoError(err)
+
+	schema, args, err := s.registry.Decode(payload)
+	s.Require().NoError(err)
+	s.Equal(s.created, schema)
+	s.Equal([]any{big.NewInt(7), owner, "title", uint64(1700000000)}, args)
+	s.Equal(`ToDoCreated(7, 0x70997970C51812dc3A010C7d01b50e0d17dc79C8, "title", 1700000000)`, schema.Format(args))
+}
+
+func (s *NoticeSuite) TestVersionsAreDistinct() {
+	v2 := s.registry.Register(2, "ToDoCreated(uint256,address,string,uint64)")
+	payload, err := v2.Encode(big.NewInt(1), common.Address{}, "", uint64(0))
+	s.Require().NoError(err)
+
+	_, err = s.created.Decode(payload)
+	s.True(errors.Is(err, ErrUnknownNotice))
+	schema, _, err := s.registry.Decode(payload)
+	s.NoError(err)
+	s.Equal(v2, schema)
+}
+
+func (s *NoticeSuite) TestDecodeUnknown() {
+	_, _, err := s.registry.Decode([]byte(`{"id":1}`))
+	s.True(errors.Is(err, ErrUnknownNotice))
+	_, _, err = s.registry.Decode([]byte{1})
+	s.True(errors.Is(err, ErrUnknownNotice))
+}
+
+func (s *NoticeSuite) TestDecodeTruncated() {
+	payload, err := s.created.Encode(big.NewInt(7), common.Address{}, "title", uint64(1))
+	s.Require().NoError(err)
+	_, _, err = s.registry.Decode(payload[:40])
+	s.True(errors.Is(err, ErrInvalidPayload))
+}
+
+func (s *NoticeSuite) TestRegisterPanics() {
+	s.Panics(func() {
+		s.registry.Register(1, "ToDoCreated(uint256,address,string,uint64)")
+	})
+	s.Panics(func() {
+		s.registry.Register(1, "ToDoCreated(notatype)")
+	})
+}
@@ -0,0 +1,101 @@
+package rollups
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// StatusException is the status of a Record whose request made Run halt
+// through /exception. It is never sent to /finish.
+const StatusException = "exception"
+
+// Record is one line of a recording: a request taken from /finish and
+// everything the application produced while handling it.
+type Record struct {
+	Request              FinishResponse               `json:"request"`
+	Status               string                       `json:"status"`
+	Notices              []NoticeRequest              `json:"notices,omitempty"`
+	Reports              []ReportRequest              `json:"reports,omitempty"`
+	Vouchers             []VoucherRequest             `json:"vouchers,omitempty"`
+	DelegateCallVouchers []DelegateCallVoucherRequest `json:"delegate_call_vouchers,omitempty"`
+	Exception            *ExceptionRequest            `json:"exception,omitempty"`
+}
+
+// ReadRecording decodes a recording written with WithRecording.
+func ReadRecording(r io.Reader) ([]Record, error) {
+	var records []Record
+	decoder := json.NewDecoder(r)
+	for decoder.More() {
+		var record Record
+		if err := decoder.Decode(&record); err != nil {
+			return nil, fmt.Errorf("rollups: record %d: %w", len(records)+1, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// recorder collects the outputs of the request being handled and appends
+// the finished Record to w as a JSON line. A nil recorder records nothing.
+type recorder struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	current *Record
+}
+
+func newRecorder(w io.Writer) *recorder {
+	return &recorder{encoder: json.NewEncoder(w)}
+}
+
+func (r *recorder) begin(request *FinishResponse) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current = &Record{Request: *request}
+}
+
+// output records v, sent to endpoint, if a request is being handled.
+func (r *recorder) output(endpoint string, v any) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.current == nil {
+		return
+	}
+	switch output := v.(type) {
+	case *NoticeRequest:
+		r.current.Notices = append(r.current.Notices, *output)
+	case *ReportRequest:
+		r.current.Reports = append(r.current.Reports, *output)
+	case *VoucherRequest:
+		r.current.Vouchers = append(r.current.Vouchers, *output)
+	case *DelegateCallVoucherRequest:
+		r.current.DelegateCallVouchers = append(r.current.DelegateCallVouchers, *output)
+	case *ExceptionRequest:
+		r.current.Exception = output
+	}
+}
+
+func (r *recorder) end(status string) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.current == nil {
+		return nil
+	}
+	record := r.current
+	r.current = nil
+	record.Status = status
+	if err := r.encoder.Encode(record); err != nil {
+		return fmt.Errorf("rollups: failed to write record: %w", err)
+	}
+	return nil
+}
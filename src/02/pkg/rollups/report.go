@@ -0,0 +1,62 @@
+package rollups
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrorReport is the JSON body of the reports emitted by ReportError.
+type ErrorReport struct {
+	Error  string       `json:"error"`
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError describes a single failed validation rule, using the JSON name
+// of the field.
+type FieldError struct {
+	Field string `json:"field"`
+	Tag   string `json:"tag"`
+	Param string `json:"param,omitempty"`
+}
+
+func NewErrorReport(cause error) ErrorReport {
+	report := ErrorReport{Error: cause.Error()}
+	var validationErrs validator.ValidationErrors
+	if errors.As(cause, &validationErrs) {
+		for _, fieldErr := range validationErrs {
+			report.Fields = append(report.Fields, FieldError{
+				Field: fieldErr.Field(),
+				Tag:   fieldErr.Tag(),
+				Param: fieldErr.Param(),
+			})
+		}
+	}
+	return report
+}
+
+// ReportError emits cause as an ErrorReport through the client in ctx.
+func ReportError(ctx context.Context, cause error) error {
+	body, err := json.Marshal(NewErrorReport(cause))
+	if err != nil {
+		return err
+	}
+	return ClientFromContext(ctx).Report(ctx, body)
+}
+
+// reportedError marks an error that was already sent as an ErrorReport, so
+// callers up the chain do not report it twice.
+type reportedError struct {
+	error
+}
+
+func (e reportedError) Unwrap() error {
+	return e.error
+}
+
+func isReported(err error) bool {
+	var reported reportedError
+	return errors.As(err, &reported)
+}
@@ -0,0 +1,359 @@
+// Package rollupstest provides an in-process fake of the rollup HTTP server,
+// so applications built on pkg/rollups can be exercised without a Cartesi
+// machine.
+package rollupstest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/codec"
+)
+
+const (
+	StatusAccept    = "accept"
+	StatusReject    = "reject"
+	StatusException = "exception"
+)
+
+var ErrTimeout = errors.New("rollupstest: timed out waiting for the application")
+
+type Voucher struct {
+	Destination string
+	Value       string
+	Payload     []byte
+}
+
+type DelegateCallVoucher struct {
+	Destination string
+	Payload     []byte
+}
+
+// Result holds everything the application produced while processing a
+// single advance or inspect request.
+type Result struct {
+	Status               string
+	Notices              [][]byte
+	Reports              [][]byte
+	Vouchers             []Voucher
+	DelegateCallVouchers []DelegateCallVoucher
+	Exception            []byte
+}
+
+type request struct {
+	finish rollups.FinishResponse
+	result *Result
+	done   chan struct{}
+}
+
+func (r *request) isInspect() bool {
+	return r.finish.Type == "inspect_state"
+}
+
+// Server implements /finish, /notice, /report, /voucher,
+// /delegate-call-voucher and /exception on top of an httptest.Server.
+type Server struct {
+	URL string
+
+	// Timeout bounds how long Advance and Inspect wait for the application
+	// to finish a request.
+	Timeout time.Duration
+	// IdleTimeout is how long /finish blocks before answering 202 when no
+	// request is queued.
+	IdleTimeout time.Duration
+
+	httpServer  *httptest.Server
+	queue       chan *request
+	mu          sync.Mutex
+	current     *request
+	outputIndex uint64
+}
+
+// NewServer starts a fake rollup server on a random local port. Callers
+// must Close it.
+func NewServer() *Server {
+	s := newServer()
+	s.httpServer = httptest.NewServer(s.handler())
+	s.URL = s.httpServer.URL
+	return s
+}
+
+// Listen starts a fake rollup server on addr, such as "127.0.0.1:5004", for
+// tools like cmd/devnode whose application is configured with a fixed
+// address. Callers must Close it.
+func Listen(addr string) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := newServer()
+	s.httpServer = httptest.NewUnstartedServer(s.handler())
+	s.httpServer.Listener.Close()
+	s.httpServer.Listener = listener
+	s.httpServer.Start()
+	s.URL = s.httpServer.URL
+	return s, nil
+}
+
+func newServer() *Server {
+	return &Server{
+		Timeout:     5 * time.Second,
+		IdleTimeout: 50 * time.Millisecond,
+		queue:       make(chan *request),
+	}
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /finish", s.handleFinish)
+	mux.HandleFunc("POST /notice", s.handleNotice)
+	mux.HandleFunc("POST /report", s.handleReport)
+	mux.HandleFunc("POST /voucher", s.handleVoucher)
+	mux.HandleFunc("POST /delegate-call-voucher", s.handleDelegateCallVoucher)
+	mux.HandleFunc("POST /exception", s.handleException)
+	return mux
+}
+
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// Client returns a rollups.Client pointed at the fake server.
+func (s *Server) Client(opts ...rollups.ClientOption) *rollups.Client {
+	return rollups.NewClient(append([]rollups.ClientOption{rollups.WithBaseURL(s.URL)}, opts...)...)
+}
+
+// Advance enqueues an advance request and blocks until the application
+// finishes processing it.
+func (s *Server) Advance(payload []byte, metadata rollups.Metadata) (*Result, error) {
+	data, err := json.Marshal(rollups.AdvanceResponse{
+		Metadata: metadata,
+		Payload:  encode(payload),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.process(rollups.FinishResponse{Type: "advance_state", Data: data})
+}
+
+// Inspect enqueues an inspect request and blocks until the application
+// finishes processing it.
+func (s *Server) Inspect(payload []byte) (*Result, error) {
+	data, err := json.Marshal(rollups.InspectResponse{
+		Payload: encode(payload),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.process(rollups.FinishResponse{Type: "inspect_state", Data: data})
+}
+
+// Process enqueues a request exactly as /finish would hand it to the
+// application, e.g. one taken from a recording, and blocks until the
+// application finishes processing it.
+func (s *Server) Process(finish rollups.FinishResponse) (*Result, error) {
+	return s.process(finish)
+}
+
+func (s *Server) process(finish rollups.FinishResponse) (*Result, error) {
+	req := &request{
+		finish: finish,
+		result: &Result{},
+		done:   make(chan struct{}),
+	}
+	timeout := time.After(s.Timeout)
+	select {
+	case s.queue <- req:
+	case <-timeout:
+		return nil, ErrTimeout
+	}
+	select {
+	case <-req.done:
+		return req.result, nil
+	case <-timeout:
+		return nil, ErrTimeout
+	}
+}
+
+// complete closes the in-flight request, if any, with the given status.
+func (s *Server) complete(status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current == nil {
+		return
+	}
+	s.current.result.Status = status
+	close(s.current.done)
+	s.current = nil
+}
+
+func (s *Server) handleFinish(w http.ResponseWriter, r *http.Request) {
+	var finish rollups.FinishRequest
+	if err := json.NewDecoder(r.Body).Decode(&finish); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if finish.Status != StatusAccept && finish.Status != StatusReject {
+		http.Error(w, fmt.Sprintf("invalid status: %q", finish.Status), http.StatusBadRequest)
+		return
+	}
+	s.complete(finish.Status)
+
+	select {
+	case req := <-s.queue:
+		s.mu.Lock()
+		s.current = req
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(req.finish)
+	case <-time.After(s.IdleTimeout):
+		w.WriteHeader(http.StatusAccepted)
+	case <-r.Context().Done():
+	}
+}
+
+// output decodes the request body into v and hands the in-flight request to
+// fn. Notices and vouchers are refused while inspecting.
+func (s *Server) output(w http.ResponseWriter, r *http.Request, v any, advanceOnly bool, fn func(req *request)) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current == nil {
+		http.Error(w, "no request is being processed", http.StatusBadRequest)
+		return false
+	}
+	if advanceOnly && s.current.isInspect() {
+		http.Error(w, "output not allowed while inspecting", http.StatusBadRequest)
+		return false
+	}
+	fn(s.current)
+	return true
+}
+
+func (s *Server) writeIndex(w http.ResponseWriter) {
+	s.mu.Lock()
+	index := s.outputIndex
+	s.outputIndex++
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rollups.IndexResponse{Index: index})
+}
+
+func (s *Server) handleNotice(w http.ResponseWriter, r *http.Request) {
+	var notice rollups.NoticeRequest
+	var err error
+	ok := s.output(w, r, &notice, true, func(req *request) {
+		var payload []byte
+		if payload, err = decode(notice.Payload); err == nil {
+			req.result.Notices = append(req.result.Notices, payload)
+		}
+	})
+	if !ok {
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.writeIndex(w)
+}
+
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	var report rollups.ReportRequest
+	var err error
+	ok := s.output(w, r, &report, false, func(req *request) {
+		var payload []byte
+		if payload, err = decode(report.Payload); err == nil {
+			req.result.Reports = append(req.result.Reports, payload)
+		}
+	})
+	if !ok {
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) handleVoucher(w http.ResponseWriter, r *http.Request) {
+	var voucher rollups.VoucherRequest
+	var err error
+	ok := s.output(w, r, &voucher, true, func(req *request) {
+		var payload []byte
+		if payload, err = decode(voucher.Payload); err == nil {
+			req.result.Vouchers = append(req.result.Vouchers, Voucher{
+				Destination: voucher.Destination,
+				Value:       voucher.Value,
+				Payload:     payload,
+			})
+		}
+	})
+	if !ok {
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.writeIndex(w)
+}
+
+func (s *Server) handleDelegateCallVoucher(w http.ResponseWriter, r *http.Request) {
+	var voucher rollups.DelegateCallVoucherRequest
+	var err error
+	ok := s.output(w, r, &voucher, true, func(req *request) {
+		var payload []byte
+		if payload, err = decode(voucher.Payload); err == nil {
+			req.result.DelegateCallVouchers = append(req.result.DelegateCallVouchers, DelegateCallVoucher{
+				Destination: voucher.Destination,
+				Payload:     payload,
+			})
+		}
+	})
+	if !ok {
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.writeIndex(w)
+}
+
+func (s *Server) handleException(w http.ResponseWriter, r *http.Request) {
+	var exception rollups.ExceptionRequest
+	var err error
+	ok := s.output(w, r, &exception, false, func(req *request) {
+		req.result.Exception, err = decode(exception.Payload)
+	})
+	if !ok {
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.complete(StatusException)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func encode(payload []byte) string {
+	return codec.EncodeHex(payload)
+}
+
+func decode(payload string) ([]byte, error) {
+	return codec.DecodeHex(payload)
+}
@@ -1,39 +1,254 @@
 package rollups
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
-type AdvanceHandlerFunc func(payload []byte, metadata Metadata) error
+var ErrPathNotFound = errors.New("path not found")
+
+type AdvanceHandlerFunc func(ctx context.Context, payload []byte, metadata Metadata) error
+
+type InspectHandlerFunc func(ctx context.Context, payload []byte) error
 
 type Router struct {
 	AdvanceHandlers map[string]AdvanceHandlerFunc
+	InspectHandlers map[string]InspectHandlerFunc
+	// Portals decides which inputs are deposits. It defaults to
+	// DefaultPortalConfig.
+	Portals PortalConfig
+
+	// inspectPatterns keeps the inspect paths with {name} segments in
+	// registration order, since they cannot be looked up in the map.
+	inspectPatterns []string
+	abiRoutes       map[[4]byte]abiRoute
+	depositHandler  AdvanceHandlerFunc
+	middlewares     []Middleware
+	signed          *signedInputs
 }
 
 func NewRouter() *Router {
 	return &Router{
 		AdvanceHandlers: make(map[string]AdvanceHandlerFunc),
+		InspectHandlers: make(map[string]InspectHandlerFunc),
+		Portals:         DefaultPortalConfig(),
+		abiRoutes:       make(map[[4]byte]abiRoute),
 	}
 }
 
-func (r *Router) HandleAdvance(path string, handler AdvanceHandlerFunc) {
-	r.AdvanceHandlers[path] = handler
+// Use appends middlewares that wrap every advance handler of the router,
+// including the ones registered before the call.
+func (r *Router) Use(middlewares ...Middleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// HandleAdvance registers handler for path, wrapped by the given route
+// middlewares.
+func (r *Router) HandleAdvance(path string, handler AdvanceHandlerFunc, middlewares ...Middleware) {
+	r.AdvanceHandlers[path] = chain(handler, middlewares)
+}
+
+// HandleDeposit registers the handler for inputs sent by one of the
+// configured portals. It receives the decoded deposit; any execLayerData the
+// depositor attached is routed afterwards as a regular input.
+func (r *Router) HandleDeposit(handler DepositHandlerFunc, middlewares ...Middleware) {
+	r.depositHandler = chain(func(ctx context.Context, payload []byte, metadata Metadata) error {
+		deposit, _ := DepositFromContext(ctx)
+		return handler(ctx, deposit, metadata)
+	}, middlewares)
 }
 
-func (r *Router) Advance(payload []byte, metadata Metadata) error {
+// Group returns a group of routes that share middlewares.
+func (r *Router) Group(middlewares ...Middleware) *Group {
+	return &Group{router: r, middlewares: middlewares}
+}
+
+// HandleInspect registers handler for an inspect path. Paths may hold
+// {name} segments, e.g. "todos/{id}", whose values are read in the handler
+// with PathParam. Static paths take precedence over patterns.
+func (r *Router) HandleInspect(path string, handler InspectHandlerFunc) {
+	path = strings.Trim(path, "/")
+	if _, ok := r.InspectHandlers[path]; !ok && strings.Contains(path, "{") {
+		r.inspectPatterns = append(r.inspectPatterns, path)
+	}
+	r.InspectHandlers[path] = handler
+}
+
+// Advance dispatches an advance input. Inputs sent by a portal go to the
+// deposit handler; ABI-encoded calldata whose selector was registered with
+// HandleABI goes to that route; anything else must be a
+// {"path": ..., "payload": ...} JSON envelope.
+func (r *Router) Advance(ctx context.Context, payload []byte, metadata Metadata) error {
+	if sender := metadata.MsgSender; r.Portals.IsPortal(sender) {
+		return r.advanceDeposit(ctx, sender, payload, metadata)
+	}
+	if route, ok := r.matchABI(payload); ok {
+		log.Printf("Router: Advance %s 0x%x", route.method.Sig, payload)
+		handler := chain(route.handler, r.middlewares)
+		return handler(withRoute(ctx, route.method.Sig), payload, metadata)
+	}
+
 	log.Println("Router: Advance", string(payload))
-	var input Input
+	// Plain inputs decode as a SignedInput without signature.
+	var input SignedInput
 	if err := json.Unmarshal(payload, &input); err != nil {
 		return err
 	}
 	handler, ok := r.AdvanceHandlers[input.Path]
 	if !ok {
-		return fmt.Errorf("handler: path not found: %s", input.Path)
+		return fmt.Errorf("handler: %w: %s", ErrPathNotFound, input.Path)
+	}
+	signed := input.Signature != ""
+	if signed {
+		if r.signed == nil {
+			return fmt.Errorf("%w: signed inputs are not accepted", ErrInvalidSignature)
+		}
+		ctx = withRelayer(ctx, metadata.MsgSender)
+		var err error
+		if metadata, err = r.signed.verify(&input, metadata); err != nil {
+			return err
+		}
+	}
+	handler = chain(handler, r.middlewares)
+	if err := handler(withRoute(ctx, input.Path), input.Payload, metadata); err != nil {
+		return err
+	}
+	if signed {
+		return r.signed.consume(&input)
+	}
+	return nil
+}
+
+// advanceDeposit hands the decoded deposit to the deposit handler and then
+// routes the execLayerData, if any, as an input sent by the depositor. The
+// deposit stays available to that route through DepositFromContext.
+//
+// Once the deposit handler succeeds the input is accepted even if the
+// execLayerData fails: rejecting it would drop the deposit while the assets
+// stay locked in the portal. The execLayerData error is sent as an
+// ErrorReport instead, unless its handler already reported it; only fatal
+// errors still stop the application.
+func (r *Router) advanceDeposit(ctx context.Context, portal common.Address, payload []byte, metadata Metadata) error {
+	deposit, execLayerData, err := r.Portals.DecodeDeposit(portal, payload)
+	if err != nil {
+		return err
 	}
-	if err := handler(input.Payload, metadata); err != nil {
+	if r.depositHandler == nil {
+		return fmt.Errorf("handler: no deposit handler registered for deposit from %s", portal)
+	}
+	ctx = withDeposit(ctx, deposit)
+	handler := chain(r.depositHandler, r.middlewares)
+	if err := handler(withRoute(ctx, "deposit"), payload, metadata); err != nil {
 		return err
 	}
+	if len(execLayerData) == 0 {
+		return nil
+	}
+	metadata.MsgSender = deposit.Depositor()
+	if err := r.Advance(ctx, execLayerData, metadata); err != nil {
+		if IsFatal(err) {
+			return err
+		}
+		err = fmt.Errorf("handler: deposit accepted, execLayerData failed: %w", err)
+		log.Println("Router:", err)
+		if isReported(err) {
+			return nil
+		}
+		return ReportError(ctx, err)
+	}
 	return nil
 }
+
+// Inspect dispatches an inspect request. The payload is either the same
+// {"path": ..., "payload": ...} envelope used by advance inputs or a bare
+// URL-style path such as "todos/42". Unknown paths are answered with an
+// error report.
+func (r *Router) Inspect(ctx context.Context, payload []byte) error {
+	log.Println("Router: Inspect", string(payload))
+	input := parseInspectInput(payload)
+	handler, params, ok := r.matchInspect(input.Path)
+	if !ok {
+		err := fmt.Errorf("handler: %w: %s", ErrPathNotFound, input.Path)
+		if reportErr := ReportError(ctx, err); reportErr != nil {
+			return errors.Join(err, reportErr)
+		}
+		return err
+	}
+	return handler(withPathParams(ctx, params), input.Payload)
+}
+
+func parseInspectInput(payload []byte) Input {
+	var input Input
+	if err := json.Unmarshal(payload, &input); err == nil && input.Path != "" {
+		input.Path = strings.Trim(input.Path, "/")
+		return input
+	}
+	return Input{Path: strings.Trim(strings.TrimSpace(string(payload)), "/")}
+}
+
+func (r *Router) matchInspect(path string) (InspectHandlerFunc, map[string]string, bool) {
+	if handler, ok := r.InspectHandlers[path]; ok {
+		return handler, nil, true
+	}
+	segments := strings.Split(path, "/")
+	for _, pattern := range r.inspectPatterns {
+		if params, ok := matchPattern(strings.Split(pattern, "/"), segments); ok {
+			return r.InspectHandlers[pattern], params, true
+		}
+	}
+	return nil, nil, false
+}
+
+func matchPattern(pattern []string, segments []string) (map[string]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, part := range pattern {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			if segments[i] == "" {
+				return nil, false
+			}
+			params[part[1:len(part)-1]] = segments[i]
+			continue
+		}
+		if part != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// Group registers advance routes on its router with a shared set of
+// middlewares, applied after the router-wide ones.
+type Group struct {
+	router      *Router
+	middlewares []Middleware
+}
+
+// Use appends middlewares to the group. Unlike Router.Use, they only wrap
+// the routes registered after the call.
+func (g *Group) Use(middlewares ...Middleware) {
+	g.middlewares = append(g.middlewares, middlewares...)
+}
+
+func (g *Group) HandleAdvance(path string, handler AdvanceHandlerFunc, middlewares ...Middleware) {
+	g.router.HandleAdvance(path, handler, append(append([]Middleware{}, g.middlewares...), middlewares...)...)
+}
+
+func (g *Group) Group(middlewares ...Middleware) *Group {
+	return &Group{
+		router:      g.router,
+		middlewares: append(append([]Middleware{}, g.middlewares...), middlewares...),
+	}
+}
+
+func (g *Group) HandleDeposit(handler DepositHandlerFunc, middlewares ...Middleware) {
+	g.router.HandleDeposit(handler, append(append([]Middleware{}, g.middlewares...), middlewares...)...)
+}
@@ -0,0 +1,165 @@
+package rollups
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestRouterSuite(t *testing.T) {
+	suite.Run(t, new(RouterSuite))
+}
+
+type RouterSuite struct {
+	suite.Suite
+	router *Router
+	calls  []string
+}
+
+func (s *RouterSuite) SetupTest() {
+	s.calls = nil
+	s.router = NewRouter()
+	s.router.HandleAdvance("echo", func(ctx context.Context, payload []byte, metadata Metadata) error {
+		s.calls = append(s.calls, "echo:"+string(payload))
+		return nil
+	})
+	s.router.HandleInspect("todos", func(ctx context.Context, payload []byte) error {
+		s.calls = append(s.calls, "todos:"+string(payload))
+		return nil
+	})
+	s.router.HandleInspect("todos/stats", func(ctx context.Context, payload []byte) error {
+		s.calls = append(s.calls, "stats")
+		return nil
+	})
+	s.router.HandleInspect("/todos/{id}/", func(ctx context.Context, payload []byte) error {
+		s.calls = append(s.calls, "todo:"+PathParam(ctx, "id"))
+		return nil
+	})
+}
+
+func (s *RouterSuite) TestAdvance() {
+	s.NoError(s.router.Advance(context.Background(), []byte(`{"path":"echo","payload":{"a":1}}`), Metadata{}))
+	s.Equal([]string{`echo:{"a":1}`}, s.calls)
+}
+
+func (s *RouterSuite) TestAdvanceUnknownPath() {
+	err := s.router.Advance(context.Background(), []byte(`{"path":"missing"}`), Metadata{})
+	s.True(errors.Is(err, ErrPathNotFound))
+}
+
+func (s *RouterSuite) TestInspectEnvelope() {
+	s.NoError(s.router.Inspect(context.Background(), []byte(`{"path":"todos","payload":{"page":1}}`)))
+	s.Equal([]string{`todos:{"page":1}`}, s.calls)
+}
+
+func (s *RouterSuite) TestInspectURLPath() {
+	s.NoError(s.router.Inspect(context.Background(), []byte("/todos/42")))
+	s.NoError(s.router.Inspect(context.Background(), []byte(`{"path":"todos/7"}`)))
+	s.Equal([]string{"todo:42", "todo:7"}, s.calls)
+}
+
+func (s *RouterSuite) TestInspectStaticPathWins() {
+	s.NoError(s.router.Inspect(context.Background(), []byte("todos/stats")))
+	s.Equal([]string{"stats"}, s.calls)
+}
+
+func (s *RouterSuite) trace(name string) Middleware {
+	return func(next AdvanceHandlerFunc) AdvanceHandlerFunc {
+		return func(ctx context.Context, payload []byte, metadata Metadata) error {
+			s.calls = append(s.calls, name)
+			return next(ctx, payload, metadata)
+		}
+	}
+}
+
+func (s *RouterSuite) TestMiddlewareOrder() {
+	group := s.router.Group(s.trace("group"))
+	group.HandleAdvance("grouped", func(ctx context.Context, payload []byte, metadata Metadata) error {
+		s.calls = append(s.calls, "handler:"+RouteFromContext(ctx))
+		return nil
+	}, s.trace("route"))
+	s.router.Use(s.trace("global"))
+
+	s.NoError(s.router.Advance(context.Background(), []byte(`{"path":"grouped"}`), Metadata{}))
+	s.Equal([]string{"global", "group", "route", "handler:grouped"}, s.calls)
+}
+
+func (s *RouterSuite) TestRecover() {
+	s.router.Use(Recover())
+	s.router.HandleAdvance("panic", func(ctx context.Context, payload []byte, metadata Metadata) error {
+		panic("boom")
+	})
+	err := s.router.Advance(context.Background(), []byte(`{"path":"panic"}`), Metadata{})
+	s.ErrorContains(err, `panic in "panic" handler: boom`)
+}
+
+func (s *RouterSuite) TestAllowSenders() {
+	s.router.HandleAdvance("admin", func(ctx context.Context, payload []byte, metadata Metadata) error {
+		return nil
+	}, AllowSenders("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"))
+
+	err := s.router.Advance(context.Background(), []byte(`{"path":"admin"}`), Metadata{
+		MsgSender: common.HexToAddress("0x70997970c51812dc3a010c7d01b50e0d17dc79c8"),
+	})
+	s.NoError(err)
+
+	err = s.router.Advance(context.Background(), []byte(`{"path":"admin"}`), Metadata{
+		MsgSender: common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+	})
+	s.True(errors.Is(err, ErrSenderNotAllowed))
+}
+
+func (s *RouterSuite) TestTiming() {
+	var timed []string
+	s.router.Use(Timing(func(path string, elapsed time.Duration) {
+		timed = append(timed, path)
+	}))
+	s.NoError(s.router.Advance(context.Background(), []byte(`{"path":"echo"}`), Metadata{}))
+	s.Equal([]string{"echo"}, timed)
+}
+
+func (s *RouterSuite) TestABIRoute() {
+	var received []any
+	s.router.HandleABI("transfer(address, uint256)", func(ctx context.Context, args []any, metadata Metadata) error {
+		s.calls = append(s.calls, RouteFromContext(ctx))
+		received = args
+		return nil
+	})
+
+	method, err := ParseSignature("transfer(address,uint256)")
+	s.Require().NoError(err)
+	s.Equal("0xa9059cbb", hexutil.Encode(method.ID))
+	to := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	args, err := method.Inputs.Pack(to, big.NewInt(1000))
+	s.Require().NoError(err)
+
+	s.NoError(s.router.Advance(context.Background(), append(method.ID, args...), Metadata{}))
+	s.NoError(s.router.Advance(context.Background(), []byte(`{"path":"echo"}`), Metadata{}))
+	s.Equal([]string{"transfer(address,uint256)", "echo:"}, s.calls)
+	s.Equal([]any{to, big.NewInt(1000)}, received)
+}
+
+func (s *RouterSuite) TestABIRouteMalformedArguments() {
+	s.router.HandleABI("deleteToDo(uint256)", func(ctx context.Context, args []any, metadata Metadata) error {
+		return nil
+	})
+	method, err := ParseSignature("deleteToDo(uint256)")
+	s.Require().NoError(err)
+	err = s.router.Advance(context.Background(), append(method.ID, 0x01), Metadata{})
+	s.True(errors.Is(err, ErrInvalidPayload))
+}
+
+func (s *RouterSuite) TestInvalidSignature() {
+	s.Panics(func() {
+		s.router.HandleABI("createToDo(string", nil)
+	})
+	s.Panics(func() {
+		s.router.HandleABI("swap((address,uint256))", nil)
+	})
+}
@@ -0,0 +1,243 @@
+package rollups
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/codec"
+)
+
+const (
+	StatusAccept = "accept"
+	StatusReject = "reject"
+
+	RequestTypeAdvance = "advance_state"
+	RequestTypeInspect = "inspect_state"
+)
+
+// ErrWrongDeployment is returned for advance inputs whose chain id or
+// application address differ from the ones Run was configured with, see
+// WithChainID and WithAppContract.
+var ErrWrongDeployment = errors.New("rollups: input addressed to another deployment")
+
+// Application is the contract between a dApp and Run. Returning an error
+// rejects the input.
+type Application interface {
+	Advance(ctx context.Context, payload []byte, metadata Metadata) error
+	Inspect(ctx context.Context, payload []byte) error
+}
+
+type runConfig struct {
+	client  *Client
+	logger  *log.Logger
+	backoff Backoff
+	// recording receives one JSON line per handled request, see
+	// WithRecording.
+	recording io.Writer
+	// chainID and appContract, when set, must match the metadata of every
+	// advance input.
+	chainID     *uint64
+	appContract *common.Address
+}
+
+type RunOption func(*runConfig)
+
+// WithClient makes Run talk to the rollup server through client instead of
+// the default one.
+func WithClient(client *Client) RunOption {
+	return func(c *runConfig) {
+		c.client = client
+	}
+}
+
+func WithRunLogger(logger *log.Logger) RunOption {
+	return func(c *runConfig) {
+		c.logger = logger
+	}
+}
+
+// WithBackoff replaces DefaultBackoff as the policy Run follows between idle
+// or failed calls to /finish.
+func WithBackoff(backoff Backoff) RunOption {
+	return func(c *runConfig) {
+		c.backoff = backoff
+	}
+}
+
+// WithRecording makes Run append a Record for every request it handles to
+// w, one JSON object per line. Outputs are captured from the context Run
+// hands to the application, so handlers must pass that context, or one
+// derived from it, to the client they send outputs with. Other uses of the
+// same client are not recorded. Read the recording back with ReadRecording.
+//
+// w is written by the application, so inside a Cartesi machine it is part of
+// the machine state: the records of rejected inputs are rolled back with
+// everything else, and the file cannot be read from outside the machine.
+// Record only when running natively, or record from the node side as
+// cmd/devnode -record does.
+func WithRecording(w io.Writer) RunOption {
+	return func(c *runConfig) {
+		c.recording = w
+	}
+}
+
+// WithChainID makes Run reject advance inputs whose metadata carries a
+// different chain id, before they reach the application.
+func WithChainID(chainID uint64) RunOption {
+	return func(c *runConfig) {
+		c.chainID = &chainID
+	}
+}
+
+// WithAppContract makes Run reject advance inputs whose metadata names a
+// different application contract, before they reach the application.
+func WithAppContract(appContract common.Address) RunOption {
+	return func(c *runConfig) {
+		c.appContract = &appContract
+	}
+}
+
+// Run owns the finish loop: it asks the rollup server for the next request,
+// hands it to app and reports accept or reject back on the following finish.
+// It returns nil once ctx is cancelled. A request already being handled is
+// allowed to complete: handlers see a context that is not cancelled with ctx.
+//
+// Idle answers and transport errors are retried following the configured
+// Backoff, re-sending the same finish status so the outcome of the last
+// input is never lost.
+//
+// With WithRecording, every handled request is appended to the recording
+// once its status is known, subject to the limits described there.
+//
+// A *FatalError returned by app, or a finish response that cannot be
+// decoded, is reported through /exception and returned, halting the loop.
+func Run(ctx context.Context, app Application, opts ...RunOption) error {
+	cfg := &runConfig{
+		client:  defaultClient,
+		logger:  log.New(os.Stderr, "[ rollups ] ", log.Lshortfile),
+		backoff: DefaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var rec *recorder
+	if cfg.recording != nil {
+		rec = newRecorder(cfg.recording)
+	}
+
+	ctx = withRecorder(NewContext(ctx, cfg.client), rec)
+	handlerCtx := context.WithoutCancel(ctx)
+	backoff := &backoffTimer{Backoff: cfg.backoff}
+	failures := 0
+	finish := FinishRequest{Status: StatusAccept}
+	for {
+		res, err := cfg.client.SendFinish(ctx, &finish)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			failures++
+			if cfg.backoff.MaxRetries > 0 && failures > cfg.backoff.MaxRetries {
+				return fmt.Errorf("rollups: finish: giving up after %d attempts: %w", failures, err)
+			}
+			cfg.logger.Printf("finish failed, retrying with status %q: %v", finish.Status, err)
+			if !backoff.wait(ctx) {
+				return nil
+			}
+			continue
+		}
+		failures = 0
+		if res.StatusCode == http.StatusAccepted {
+			res.Body.Close()
+			if !backoff.wait(ctx) {
+				return nil
+			}
+			continue
+		}
+		backoff.reset()
+		if res.StatusCode != http.StatusOK {
+			res.Body.Close()
+			return &HTTPError{Endpoint: "finish", StatusCode: res.StatusCode}
+		}
+
+		var response FinishResponse
+		err = json.NewDecoder(res.Body).Decode(&response)
+		res.Body.Close()
+		if err != nil {
+			return throw(handlerCtx, cfg.client, nil, Fatalf("rollups: finish: failed to decode response: %w", err))
+		}
+
+		rec.begin(&response)
+		finish.Status = StatusAccept
+		if err := cfg.handle(handlerCtx, app, &response); err != nil {
+			if IsFatal(err) {
+				err = throw(handlerCtx, cfg.client, &response, err)
+				if recErr := rec.end(StatusException); recErr != nil {
+					cfg.logger.Println(recErr)
+				}
+				return err
+			}
+			cfg.logger.Println(err)
+			finish.Status = StatusReject
+		}
+		if err := rec.end(finish.Status); err != nil {
+			cfg.logger.Println(err)
+		}
+	}
+}
+
+func (c *runConfig) handle(ctx context.Context, app Application, response *FinishResponse) error {
+	switch response.Type {
+	case RequestTypeAdvance:
+		var data AdvanceResponse
+		if err := json.Unmarshal(response.Data, &data); err != nil {
+			return fmt.Errorf("rollups: failed to decode advance request: %w", err)
+		}
+		if err := c.checkDeployment(&data.Metadata); err != nil {
+			return err
+		}
+		payload, err := decodePayload(data.Payload)
+		if err != nil {
+			return err
+		}
+		return app.Advance(ctx, payload, data.Metadata)
+	case RequestTypeInspect:
+		var data InspectResponse
+		if err := json.Unmarshal(response.Data, &data); err != nil {
+			return fmt.Errorf("rollups: failed to decode inspect request: %w", err)
+		}
+		payload, err := decodePayload(data.Payload)
+		if err != nil {
+			return err
+		}
+		return app.Inspect(ctx, payload)
+	default:
+		return fmt.Errorf("rollups: unknown request type: %q", response.Type)
+	}
+}
+
+func (c *runConfig) checkDeployment(metadata *Metadata) error {
+	if c.chainID != nil && metadata.ChainID != *c.chainID {
+		return fmt.Errorf("%w: chain id %d, want %d", ErrWrongDeployment, metadata.ChainID, *c.chainID)
+	}
+	if c.appContract != nil && metadata.AppContract != *c.appContract {
+		return fmt.Errorf("%w: app contract %s, want %s", ErrWrongDeployment, metadata.AppContract, *c.appContract)
+	}
+	return nil
+}
+
+func decodePayload(payload string) ([]byte, error) {
+	decoded, err := codec.DecodeHex(payload)
+	if err != nil {
+		return nil, fmt.Errorf("rollups: failed to decode payload: %w", err)
+	}
+	return decoded, nil
+}
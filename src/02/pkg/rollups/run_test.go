@@ -0,0 +1,318 @@
+package rollups_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/codec"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/rollupstest"
+	"github.com/stretchr/testify/suite"
+)
+
+type echoApplication struct {
+	client *rollups.Client
+}
+
+func (a *echoApplication) Advance(ctx context.Context, payload []byte, metadata rollups.Metadata) error {
+	if len(payload) == 0 {
+		return errors.New("empty payload")
+	}
+	if string(payload) == "fatal" {
+		return rollups.Fatal(errors.New("broken invariant"))
+	}
+	_, err := a.client.Notice(ctx, payload)
+	return err
+}
+
+func (a *echoApplication) Inspect(ctx context.Context, payload []byte) error {
+	return a.client.Report(ctx, payload)
+}
+
+func TestRunSuite(t *testing.T) {
+	suite.Run(t, new(RunSuite))
+}
+
+type RunSuite struct {
+	suite.Suite
+	server *rollupstest.Server
+	cancel context.CancelFunc
+	done   chan error
+}
+
+func (s *RunSuite) SetupTest() {
+	s.server = rollupstest.NewServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan error, 1)
+	client := s.server.Client()
+	go func() {
+		s.done <- rollups.Run(ctx, &echoApplication{client: client}, rollups.WithClient(client))
+	}()
+}
+
+func (s *RunSuite) TearDownTest() {
+	s.cancel()
+	s.NoError(<-s.done)
+	s.server.Close()
+}
+
+func (s *RunSuite) TestAdvanceAccept() {
+	res, err := s.server.Advance([]byte("hello"), rollups.Metadata{InputIndex: 0})
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Equal([][]byte{[]byte("hello")}, res.Notices)
+}
+
+func (s *RunSuite) TestAdvanceReject() {
+	res, err := s.server.Advance(nil, rollups.Metadata{InputIndex: 0})
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusReject, res.Status)
+	s.Empty(res.Notices)
+}
+
+func (s *RunSuite) TestAdvanceFatal() {
+	res, err := s.server.Advance([]byte("fatal"), rollups.Metadata{InputIndex: 4})
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusException, res.Status)
+	s.JSONEq(`{"error":"fatal: broken invariant","request_type":"advance_state","input_index":4}`, string(res.Exception))
+
+	err = <-s.done
+	s.True(rollups.IsFatal(err))
+	s.done <- nil
+}
+
+func (s *RunSuite) TestDelegateCallVoucher() {
+	server := rollupstest.NewServer()
+	defer server.Close()
+	client := server.Client()
+	router := rollups.NewRouter()
+	router.HandleAdvance("delegate", func(ctx context.Context, payload []byte, metadata rollups.Metadata) error {
+		_, err := client.SendDelegateCallVoucher(ctx, &rollups.DelegateCallVoucherRequest{
+			Destination: "0xab7528bb862fb57e8a2bcd567a2e929a0be56a5e",
+			Payload:     "0xdeadbeef",
+		})
+		return err
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- rollups.Run(ctx, router, rollups.WithClient(client))
+	}()
+
+	res, err := server.Advance([]byte(`{"path":"delegate"}`), rollups.Metadata{})
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Equal([]rollupstest.DelegateCallVoucher{{
+		Destination: "0xab7528bb862fb57e8a2bcd567a2e929a0be56a5e",
+		Payload:     []byte{0xde, 0xad, 0xbe, 0xef},
+	}}, res.DelegateCallVouchers)
+	cancel()
+	s.NoError(<-done)
+}
+
+func (s *RunSuite) TestRecording() {
+	var recording bytes.Buffer
+	server := rollupstest.NewServer()
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	client := server.Client()
+	go func() {
+		done <- rollups.Run(ctx, &echoApplication{client: client}, rollups.WithClient(client), rollups.WithRecording(&recording))
+	}()
+
+	_, err := server.Advance([]byte("hello"), rollups.Metadata{InputIndex: 0})
+	s.Require().NoError(err)
+	_, err = server.Advance(nil, rollups.Metadata{InputIndex: 1})
+	s.Require().NoError(err)
+	_, err = server.Inspect([]byte("state"))
+	s.Require().NoError(err)
+	cancel()
+	s.NoError(<-done)
+
+	records, err := rollups.ReadRecording(&recording)
+	s.Require().NoError(err)
+	s.Require().Len(records, 3)
+	s.Equal(rollups.RequestTypeAdvance, records[0].Request.Type)
+	s.Equal(rollups.StatusAccept, records[0].Status)
+	s.Equal([]rollups.NoticeRequest{{Payload: codec.EncodeHex([]byte("hello"))}}, records[0].Notices)
+	s.Equal(rollups.StatusReject, records[1].Status)
+	s.Empty(records[1].Notices)
+	s.Equal(rollups.RequestTypeInspect, records[2].Request.Type)
+	s.Equal([]rollups.ReportRequest{{Payload: codec.EncodeHex([]byte("state"))}}, records[2].Reports)
+}
+
+// detachedApplication reports the payload twice, once with the context
+// Run passed in and once with a context of its own.
+type detachedApplication struct {
+	echoApplication
+}
+
+func (a *detachedApplication) Inspect(ctx context.Context, payload []byte) error {
+	if err := a.client.Report(ctx, payload); err != nil {
+		return err
+	}
+	return a.client.Report(context.Background(), []byte("detached"))
+}
+
+func (s *RunSuite) TestRecordingIsPerRun() {
+	var recording bytes.Buffer
+	server := rollupstest.NewServer()
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	client := server.Client()
+	go func() {
+		app := &detachedApplication{echoApplication{client: client}}
+		done <- rollups.Run(ctx, app, rollups.WithClient(client), rollups.WithRecording(&recording))
+	}()
+
+	res, err := server.Inspect([]byte("state"))
+	s.Require().NoError(err)
+	s.Equal([][]byte{[]byte("state"), []byte("detached")}, res.Reports)
+	cancel()
+	s.NoError(<-done)
+
+	records, err := rollups.ReadRecording(&recording)
+	s.Require().NoError(err)
+	s.Require().Len(records, 1)
+	s.Equal([]rollups.ReportRequest{{Payload: codec.EncodeHex([]byte("state"))}}, records[0].Reports)
+}
+
+func (s *RunSuite) TestWrongDeployment() {
+	appContract := common.HexToAddress("0xab7528bb862fb57e8a2bcd567a2e929a0be56a5e")
+	server := rollupstest.NewServer()
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	client := server.Client()
+	go func() {
+		done <- rollups.Run(ctx, &echoApplication{client: client}, rollups.WithClient(client),
+			rollups.WithChainID(13370), rollups.WithAppContract(appContract))
+	}()
+
+	res, err := server.Advance([]byte("hello"), rollups.Metadata{ChainID: 13370, AppContract: appContract})
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusAccept, res.Status)
+
+	res, err = server.Advance([]byte("hello"), rollups.Metadata{ChainID: 1, AppContract: appContract})
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusReject, res.Status)
+	s.Empty(res.Notices)
+
+	res, err = server.Advance([]byte("hello"), rollups.Metadata{ChainID: 13370})
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusReject, res.Status)
+	cancel()
+	s.NoError(<-done)
+}
+
+func (s *RunSuite) TestMetadataJSON() {
+	var metadata rollups.Metadata
+	err := json.Unmarshal([]byte(`{
+		"chain_id": 13370,
+		"app_contract": "0xab7528bb862fb57e8a2bcd567a2e929a0be56a5e",
+		"msg_sender": "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		"input_index": 2,
+		"prev_randao": "0x2a"
+	}`), &metadata)
+	s.Require().NoError(err)
+	s.Equal(uint64(13370), metadata.ChainID)
+	s.Equal(common.HexToAddress("0xab7528bb862fb57e8a2bcd567a2e929a0be56a5e"), metadata.AppContract)
+	s.Equal(common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"), metadata.MsgSender)
+	s.Equal(uint64(2), metadata.InputIndex)
+	s.Equal(common.BigToHash(big.NewInt(42)), metadata.PrevRandao)
+
+	s.NoError(json.Unmarshal([]byte(`{"prev_randao": ""}`), &metadata))
+	s.Error(json.Unmarshal([]byte(`{"msg_sender": "0x1234"}`), &metadata))
+	s.Error(json.Unmarshal([]byte(`{"prev_randao": "2a"}`), &metadata))
+}
+
+func (s *RunSuite) TestInspect() {
+	res, err := s.server.Inspect([]byte("state"))
+	s.Require().NoError(err)
+	s.Equal(rollupstest.StatusAccept, res.Status)
+	s.Equal([][]byte{[]byte("state")}, res.Reports)
+}
+
+func (s *RunSuite) TestOutputWithoutPendingRequest() {
+	app := &echoApplication{client: s.server.Client()}
+	s.Error(app.Advance(context.Background(), []byte("outside"), rollups.Metadata{}))
+}
+
+// finishServer answers /finish with the given handlers in turn, recording the
+// status sent on each call. Handlers past the end answer 202.
+func finishServer(handlers ...http.HandlerFunc) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var statuses []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var finish rollups.FinishRequest
+		json.NewDecoder(r.Body).Decode(&finish)
+		mu.Lock()
+		call := len(statuses)
+		statuses = append(statuses, finish.Status)
+		mu.Unlock()
+		if call < len(handlers) {
+			handlers[call](w, r)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), statuses...)
+	}
+}
+
+func abort(w http.ResponseWriter, r *http.Request) {
+	panic(http.ErrAbortHandler)
+}
+
+func (s *RunSuite) TestTransportErrorKeepsFinishStatus() {
+	server, statuses := finishServer(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"request_type":"advance_state","data":{"metadata":{},"payload":"0x"}}`))
+		},
+		abort,
+		abort,
+	)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := rollups.NewClient(rollups.WithBaseURL(server.URL))
+	done := make(chan error, 1)
+	go func() {
+		done <- rollups.Run(ctx, &echoApplication{client: client},
+			rollups.WithClient(client),
+			rollups.WithBackoff(rollups.Backoff{Initial: time.Millisecond, Max: 5 * time.Millisecond, Multiplier: 2}),
+		)
+	}()
+	s.Eventually(func() bool { return len(statuses()) >= 4 }, time.Second, time.Millisecond)
+	cancel()
+	s.NoError(<-done)
+	s.Equal([]string{rollups.StatusAccept, rollups.StatusReject, rollups.StatusReject, rollups.StatusReject}, statuses()[:4])
+}
+
+func (s *RunSuite) TestTransportErrorGivesUp() {
+	server, statuses := finishServer(abort, abort, abort, abort)
+	defer server.Close()
+
+	client := rollups.NewClient(rollups.WithBaseURL(server.URL))
+	err := rollups.Run(context.Background(), &echoApplication{client: client},
+		rollups.WithClient(client),
+		rollups.WithBackoff(rollups.Backoff{Initial: time.Millisecond, MaxRetries: 2}),
+	)
+	s.Error(err)
+	s.Len(statuses(), 3)
+}
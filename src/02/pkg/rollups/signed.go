@@ -0,0 +1,190 @@
+package rollups
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups/codec"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrInvalidNonce     = errors.New("invalid nonce")
+)
+
+// NonceStore keeps, for every signer of signed inputs, the nonce its next
+// input must carry.
+type NonceStore interface {
+	FindNonce(signer common.Address) (uint64, error)
+	UpdateNonce(signer common.Address, nonce uint64) error
+}
+
+// SignedInput is a {"path": ..., "payload": ...} input signed by Signer
+// with EIP-712, so anyone can relay it through the InputBox on the
+// signer's behalf. Payload is signed as the exact JSON text it holds, so
+// clients should sign and send it compacted, as encoding/json marshals it.
+type SignedInput struct {
+	Path      string          `json:"path"`
+	Payload   json.RawMessage `json:"payload"`
+	Signer    common.Address  `json:"signer"`
+	Nonce     uint64          `json:"nonce"`
+	Signature string          `json:"signature"`
+}
+
+// SignedInputDomain is the EIP-712 domain of signed inputs. The chain id and
+// verifying contract are taken from the input metadata, so a signature is
+// only valid for the deployment it was made for.
+type SignedInputDomain struct {
+	Name    string
+	Version string
+}
+
+// TypedData returns the EIP-712 typed data a signer signs for input:
+//
+//	Input(string path,string payload,uint256 nonce)
+func (d SignedInputDomain) TypedData(chainID uint64, appContract common.Address, input *SignedInput) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Input": {
+				{Name: "path", Type: "string"},
+				{Name: "payload", Type: "string"},
+				{Name: "nonce", Type: "uint256"},
+			},
+		},
+		PrimaryType: "Input",
+		Domain: apitypes.TypedDataDomain{
+			Name:              d.Name,
+			Version:           d.Version,
+			ChainId:           (*math.HexOrDecimal256)(new(big.Int).SetUint64(chainID)),
+			VerifyingContract: appContract.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"path":    input.Path,
+			"payload": string(input.Payload),
+			"nonce":   new(big.Int).SetUint64(input.Nonce),
+		},
+	}
+}
+
+// Hash returns the EIP-712 digest of input.
+func (d SignedInputDomain) Hash(chainID uint64, appContract common.Address, input *SignedInput) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(d.TypedData(chainID, appContract, input))
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash signed input: %w", err)
+	}
+	return hash, nil
+}
+
+// Sign sets input.Signer and input.Signature for key. It is meant for
+// tests and tools; wallets sign the same digest with eth_signTypedData_v4.
+func (d SignedInputDomain) Sign(key *ecdsa.PrivateKey, chainID uint64, appContract common.Address, input *SignedInput) error {
+	hash, err := d.Hash(chainID, appContract, input)
+	if err != nil {
+		return err
+	}
+	signature, err := crypto.Sign(hash, key)
+	if err != nil {
+		return fmt.Errorf("failed to sign input: %w", err)
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	input.Signer = crypto.PubkeyToAddress(key.PublicKey)
+	input.Signature = codec.EncodeHex(signature)
+	return nil
+}
+
+// Recover checks that input.Signature was made by input.Signer.
+func (d SignedInputDomain) Recover(chainID uint64, appContract common.Address, input *SignedInput) (common.Address, error) {
+	signature, err := codec.DecodeHex(input.Signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("%w: %d bytes, want %d", ErrInvalidSignature, len(signature), crypto.SignatureLength)
+	}
+	signature = append([]byte(nil), signature...)
+	if v := signature[crypto.RecoveryIDOffset]; v == 27 || v == 28 {
+		signature[crypto.RecoveryIDOffset] -= 27
+	}
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:64])
+	if !crypto.ValidateSignatureValues(signature[crypto.RecoveryIDOffset], r, s, true) {
+		return common.Address{}, fmt.Errorf("%w: malformed r, s or v", ErrInvalidSignature)
+	}
+
+	hash, err := d.Hash(chainID, appContract, input)
+	if err != nil {
+		return common.Address{}, err
+	}
+	pub, err := crypto.SigToPub(hash, signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if signer := crypto.PubkeyToAddress(*pub); signer != input.Signer {
+		return common.Address{}, fmt.Errorf("%w: signed by %s, not %s", ErrInvalidSignature, signer, input.Signer)
+	}
+	return input.Signer, nil
+}
+
+type signedInputs struct {
+	domain SignedInputDomain
+	nonces NonceStore
+}
+
+// AcceptSignedInputs makes the router take SignedInput envelopes besides
+// plain JSON inputs. The route runs with the recovered signer as
+// metadata.MsgSender and the relayer available through RelayerFromContext.
+// Each signer's nonces must be used in order, starting at 0; the nonce is
+// consumed once the route accepts the input.
+func (r *Router) AcceptSignedInputs(domain SignedInputDomain, nonces NonceStore) {
+	r.signed = &signedInputs{domain: domain, nonces: nonces}
+}
+
+// verify recovers the signer of input and checks its nonce. It returns the
+// metadata the route runs with.
+func (s *signedInputs) verify(input *SignedInput, metadata Metadata) (Metadata, error) {
+	signer, err := s.domain.Recover(metadata.ChainID, metadata.AppContract, input)
+	if err != nil {
+		return metadata, err
+	}
+	nonce, err := s.nonces.FindNonce(signer)
+	if err != nil {
+		return metadata, fmt.Errorf("failed to find nonce of %s: %w", signer, err)
+	}
+	if input.Nonce != nonce {
+		return metadata, fmt.Errorf("%w: %s sent nonce %d, want %d", ErrInvalidNonce, signer, input.Nonce, nonce)
+	}
+	metadata.MsgSender = signer
+	return metadata, nil
+}
+
+func (s *signedInputs) consume(input *SignedInput) error {
+	if err := s.nonces.UpdateNonce(input.Signer, input.Nonce+1); err != nil {
+		return fmt.Errorf("failed to update nonce of %s: %w", input.Signer, err)
+	}
+	return nil
+}
+
+// RelayerFromContext returns the msg_sender that submitted the signed input
+// being handled. It reports false for inputs that were not signed.
+func RelayerFromContext(ctx context.Context) (common.Address, bool) {
+	relayer, ok := ctx.Value(relayerKey).(common.Address)
+	return relayer, ok
+}
+
+func withRelayer(ctx context.Context, relayer common.Address) context.Context {
+	return context.WithValue(ctx, relayerKey, relayer)
+}
@@ -0,0 +1,147 @@
+package rollups
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/suite"
+)
+
+type memoryNonces map[common.Address]uint64
+
+func (n memoryNonces) FindNonce(signer common.Address) (uint64, error) {
+	return n[signer], nil
+}
+
+func (n memoryNonces) UpdateNonce(signer common.Address, nonce uint64) error {
+	n[signer] = nonce
+	return nil
+}
+
+func TestSignedInputSuite(t *testing.T) {
+	suite.Run(t, new(SignedInputSuite))
+}
+
+type SignedInputSuite struct {
+	suite.Suite
+	domain   SignedInputDomain
+	key      *ecdsa.PrivateKey
+	signer   common.Address
+	metadata Metadata
+	nonces   memoryNonces
+	router   *Router
+	calls    []Metadata
+	relayers []common.Address
+	fail     bool
+}
+
+func (s *SignedInputSuite) SetupTest() {
+	var err error
+	s.key, err = crypto.GenerateKey()
+	s.Require().NoError(err)
+	s.signer = crypto.PubkeyToAddress(s.key.PublicKey)
+	s.domain = SignedInputDomain{Name: "Test", Version: "1"}
+	s.metadata = Metadata{
+		ChainID:     13370,
+		AppContract: common.HexToAddress("0xab7528bb862fb57e8a2bcd567a2e929a0be56a5e"),
+		MsgSender:   common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+	}
+	s.nonces = memoryNonces{}
+	s.calls = nil
+	s.relayers = nil
+	s.fail = false
+
+	s.router = NewRouter()
+	s.router.AcceptSignedInputs(s.domain, s.nonces)
+	s.router.HandleAdvance("echo", func(ctx context.Context, payload []byte, metadata Metadata) error {
+		if s.fail {
+			return errors.New("rejected")
+		}
+		relayer, _ := RelayerFromContext(ctx)
+		s.calls = append(s.calls, metadata)
+		s.relayers = append(s.relayers, relayer)
+		return nil
+	})
+}
+
+func (s *SignedInputSuite) signed(nonce uint64) []byte {
+	input := &SignedInput{Path: "echo", Payload: json.RawMessage(`{"a":1}`), Nonce: nonce}
+	s.Require().NoError(s.domain.Sign(s.key, s.metadata.ChainID, s.metadata.AppContract, input))
+	payload, err := json.Marshal(input)
+	s.Require().NoError(err)
+	return payload
+}
+
+func (s *SignedInputSuite) TestSignerIsSender() {
+	s.NoError(s.router.Advance(context.Background(), s.signed(0), s.metadata))
+	s.Require().Len(s.calls, 1)
+	s.Equal(s.signer, s.calls[0].MsgSender)
+	s.Equal(s.metadata.MsgSender, s.relayers[0])
+	s.Equal(uint64(1), s.nonces[s.signer])
+}
+
+func (s *SignedInputSuite) TestReplayRejected() {
+	payload := s.signed(0)
+	s.NoError(s.router.Advance(context.Background(), payload, s.metadata))
+	err := s.router.Advance(context.Background(), payload, s.metadata)
+	s.True(errors.Is(err, ErrInvalidNonce))
+	s.NoError(s.router.Advance(context.Background(), s.signed(1), s.metadata))
+	s.Len(s.calls, 2)
+}
+
+func (s *SignedInputSuite) TestNonceKeptWhenRejected() {
+	s.fail = true
+	s.Error(s.router.Advance(context.Background(), s.signed(0), s.metadata))
+	s.fail = false
+	s.NoError(s.router.Advance(context.Background(), s.signed(0), s.metadata))
+}
+
+func (s *SignedInputSuite) TestOtherDeployment() {
+	payload := s.signed(0)
+	other := s.metadata
+	other.AppContract = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	err := s.router.Advance(context.Background(), payload, other)
+	s.True(errors.Is(err, ErrInvalidSignature))
+	s.Empty(s.calls)
+}
+
+func (s *SignedInputSuite) TestLargeChainID() {
+	s.metadata.ChainID = math.MaxUint64
+	domain := s.domain.TypedData(s.metadata.ChainID, s.metadata.AppContract, &SignedInput{})
+	s.Equal(new(big.Int).SetUint64(math.MaxUint64), (*big.Int)(domain.Domain.ChainId))
+	s.NoError(s.router.Advance(context.Background(), s.signed(0), s.metadata))
+	s.Len(s.calls, 1)
+}
+
+func (s *SignedInputSuite) TestTamperedPayload() {
+	var input SignedInput
+	s.Require().NoError(json.Unmarshal(s.signed(0), &input))
+	input.Payload = json.RawMessage(`{"a":2}`)
+	payload, err := json.Marshal(input)
+	s.Require().NoError(err)
+	err = s.router.Advance(context.Background(), payload, s.metadata)
+	s.True(errors.Is(err, ErrInvalidSignature))
+}
+
+func (s *SignedInputSuite) TestUnsignedInput() {
+	s.NoError(s.router.Advance(context.Background(), []byte(`{"path":"echo"}`), s.metadata))
+	s.Require().Len(s.calls, 1)
+	s.Equal(s.metadata.MsgSender, s.calls[0].MsgSender)
+	s.Equal(common.Address{}, s.relayers[0])
+}
+
+func (s *SignedInputSuite) TestNotAccepted() {
+	router := NewRouter()
+	router.HandleAdvance("echo", func(ctx context.Context, payload []byte, metadata Metadata) error {
+		return nil
+	})
+	err := router.Advance(context.Background(), s.signed(0), s.metadata)
+	s.True(errors.Is(err, ErrInvalidSignature))
+}
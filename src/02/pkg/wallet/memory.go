@@ -0,0 +1,80 @@
+package wallet
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type erc20Key struct {
+	token common.Address
+	owner common.Address
+}
+
+type erc721Key struct {
+	token   common.Address
+	tokenId string
+}
+
+// MemoryStore is a Store kept in process memory.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	ether  map[common.Address]*big.Int
+	erc20  map[erc20Key]*big.Int
+	erc721 map[erc721Key]common.Address
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		ether:  make(map[common.Address]*big.Int),
+		erc20:  make(map[erc20Key]*big.Int),
+		erc721: make(map[erc721Key]common.Address),
+	}
+}
+
+func (s *MemoryStore) FindEtherBalance(owner common.Address) (*big.Int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return balanceOrZero(s.ether[owner]), nil
+}
+
+func (s *MemoryStore) UpdateEtherBalance(owner common.Address, value *big.Int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ether[owner] = new(big.Int).Set(value)
+	return nil
+}
+
+func (s *MemoryStore) FindERC20Balance(token common.Address, owner common.Address) (*big.Int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return balanceOrZero(s.erc20[erc20Key{token, owner}]), nil
+}
+
+func (s *MemoryStore) UpdateERC20Balance(token common.Address, owner common.Address, value *big.Int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.erc20[erc20Key{token, owner}] = new(big.Int).Set(value)
+	return nil
+}
+
+func (s *MemoryStore) FindERC721Owner(token common.Address, tokenId *big.Int) (common.Address, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.erc721[erc721Key{token, tokenId.String()}], nil
+}
+
+func (s *MemoryStore) UpdateERC721Owner(token common.Address, tokenId *big.Int, owner common.Address) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.erc721[erc721Key{token, tokenId.String()}] = owner
+	return nil
+}
+
+func balanceOrZero(balance *big.Int) *big.Int {
+	if balance == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(balance)
+}
@@ -0,0 +1,292 @@
+// Package wallet keeps the Ether, ERC20 and ERC721 ledger of a pkg/rollups
+// application: it credits portal deposits, moves assets between accounts and
+// turns withdrawals into vouchers.
+//
+// ERC1155 tokens are not supported. Deposit returns ErrUnsupportedAsset for
+// them and credits nothing, and there is no way to withdraw them. Rejecting
+// the input does not give the tokens back either, since they stay in the
+// portal. Applications should accept such deposits and report the error to
+// the depositor.
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+)
+
+var (
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrBalanceOverflow   = errors.New("balance overflow")
+	ErrInvalidAmount     = errors.New("invalid amount")
+	ErrNotTokenOwner     = errors.New("not the token owner")
+	ErrUnsupportedAsset  = errors.New("unsupported asset")
+	// ErrCorruptedState is returned when the store holds a balance no
+	// sequence of valid operations could have produced.
+	ErrCorruptedState = errors.New("corrupted ledger state")
+)
+
+var MaxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// Store persists the ledger. Missing balances read as zero and tokens that
+// were never deposited are owned by the zero address.
+type Store interface {
+	FindEtherBalance(owner common.Address) (*big.Int, error)
+	UpdateEtherBalance(owner common.Address, value *big.Int) error
+	FindERC20Balance(token common.Address, owner common.Address) (*big.Int, error)
+	UpdateERC20Balance(token common.Address, owner common.Address, value *big.Int) error
+	FindERC721Owner(token common.Address, tokenId *big.Int) (common.Address, error)
+	UpdateERC721Owner(token common.Address, tokenId *big.Int, owner common.Address) error
+}
+
+type Wallet struct {
+	store Store
+}
+
+func NewWallet(store Store) *Wallet {
+	return &Wallet{store: store}
+}
+
+// Deposit credits the depositor with the asset carried by a portal deposit.
+func (w *Wallet) Deposit(deposit rollups.Deposit) error {
+	switch d := deposit.(type) {
+	case *rollups.EtherDeposit:
+		balance, err := w.store.FindEtherBalance(d.Sender)
+		if err != nil {
+			return err
+		}
+		balance, err = credit(balance, d.Value)
+		if err != nil {
+			return err
+		}
+		return w.store.UpdateEtherBalance(d.Sender, balance)
+	case *rollups.ERC20Deposit:
+		balance, err := w.store.FindERC20Balance(d.Token, d.Sender)
+		if err != nil {
+			return err
+		}
+		balance, err = credit(balance, d.Value)
+		if err != nil {
+			return err
+		}
+		return w.store.UpdateERC20Balance(d.Token, d.Sender, balance)
+	case *rollups.ERC721Deposit:
+		return w.store.UpdateERC721Owner(d.Token, d.TokenId, d.Sender)
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedAsset, deposit)
+	}
+}
+
+func (w *Wallet) EtherBalanceOf(owner common.Address) (*big.Int, error) {
+	return w.store.FindEtherBalance(owner)
+}
+
+func (w *Wallet) ERC20BalanceOf(token common.Address, owner common.Address) (*big.Int, error) {
+	return w.store.FindERC20Balance(token, owner)
+}
+
+func (w *Wallet) ERC721OwnerOf(token common.Address, tokenId *big.Int) (common.Address, error) {
+	return w.store.FindERC721Owner(token, tokenId)
+}
+
+func (w *Wallet) EtherTransfer(from common.Address, to common.Address, value *big.Int) error {
+	if from == to {
+		return nil
+	}
+	fromBalance, toBalance, err := transfer(
+		func(owner common.Address) (*big.Int, error) { return w.store.FindEtherBalance(owner) },
+		from, to, value,
+	)
+	if err != nil {
+		return err
+	}
+	if err := w.store.UpdateEtherBalance(from, fromBalance); err != nil {
+		return err
+	}
+	return w.store.UpdateEtherBalance(to, toBalance)
+}
+
+func (w *Wallet) ERC20Transfer(token common.Address, from common.Address, to common.Address, value *big.Int) error {
+	if from == to {
+		return nil
+	}
+	fromBalance, toBalance, err := transfer(
+		func(owner common.Address) (*big.Int, error) { return w.store.FindERC20Balance(token, owner) },
+		from, to, value,
+	)
+	if err != nil {
+		return err
+	}
+	if err := w.store.UpdateERC20Balance(token, from, fromBalance); err != nil {
+		return err
+	}
+	return w.store.UpdateERC20Balance(token, to, toBalance)
+}
+
+func (w *Wallet) ERC721Transfer(token common.Address, from common.Address, to common.Address, tokenId *big.Int) error {
+	if err := w.checkERC721Owner(token, from, tokenId); err != nil {
+		return err
+	}
+	return w.store.UpdateERC721Owner(token, tokenId, to)
+}
+
+// EtherWithdraw debits value from owner and returns the voucher that sends
+// it back on the base layer.
+func (w *Wallet) EtherWithdraw(owner common.Address, value *big.Int) (*rollups.VoucherRequest, error) {
+	balance, err := w.store.FindEtherBalance(owner)
+	if err != nil {
+		return nil, err
+	}
+	balance, err = debit(balance, value)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.store.UpdateEtherBalance(owner, balance); err != nil {
+		return nil, err
+	}
+	return &rollups.VoucherRequest{
+		Destination: owner.Hex(),
+		Value:       encodeValue(value),
+		Payload:     hexutil.Encode(nil),
+	}, nil
+}
+
+// ERC20Withdraw debits value from owner and returns a voucher calling
+// transfer(owner, value) on the token.
+func (w *Wallet) ERC20Withdraw(token common.Address, owner common.Address, value *big.Int) (*rollups.VoucherRequest, error) {
+	balance, err := w.store.FindERC20Balance(token, owner)
+	if err != nil {
+		return nil, err
+	}
+	balance, err = debit(balance, value)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := erc20ABI.Pack("transfer", owner, value)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.store.UpdateERC20Balance(token, owner, balance); err != nil {
+		return nil, err
+	}
+	return &rollups.VoucherRequest{
+		Destination: token.Hex(),
+		Value:       encodeValue(common.Big0),
+		Payload:     hexutil.Encode(payload),
+	}, nil
+}
+
+// ERC721Withdraw releases tokenId to owner and returns a voucher calling
+// safeTransferFrom(application, owner, tokenId) on the token.
+func (w *Wallet) ERC721Withdraw(application common.Address, token common.Address, owner common.Address, tokenId *big.Int) (*rollups.VoucherRequest, error) {
+	if err := w.checkERC721Owner(token, owner, tokenId); err != nil {
+		return nil, err
+	}
+	payload, err := erc721ABI.Pack("safeTransferFrom", application, owner, tokenId)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.store.UpdateERC721Owner(token, tokenId, common.Address{}); err != nil {
+		return nil, err
+	}
+	return &rollups.VoucherRequest{
+		Destination: token.Hex(),
+		Value:       encodeValue(common.Big0),
+		Payload:     hexutil.Encode(payload),
+	}, nil
+}
+
+func (w *Wallet) checkERC721Owner(token common.Address, owner common.Address, tokenId *big.Int) error {
+	current, err := w.store.FindERC721Owner(token, tokenId)
+	if err != nil {
+		return err
+	}
+	if current != owner || owner == (common.Address{}) {
+		return fmt.Errorf("%w: %s does not own token %s of %s", ErrNotTokenOwner, owner, tokenId, token)
+	}
+	return nil
+}
+
+func transfer(
+	balanceOf func(common.Address) (*big.Int, error),
+	from common.Address,
+	to common.Address,
+	value *big.Int,
+) (*big.Int, *big.Int, error) {
+	fromBalance, err := balanceOf(from)
+	if err != nil {
+		return nil, nil, err
+	}
+	toBalance, err := balanceOf(to)
+	if err != nil {
+		return nil, nil, err
+	}
+	fromBalance, err = debit(fromBalance, value)
+	if err != nil {
+		return nil, nil, err
+	}
+	toBalance, err = credit(toBalance, value)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fromBalance, toBalance, nil
+}
+
+func credit(balance *big.Int, value *big.Int) (*big.Int, error) {
+	if err := checkStored(balance); err != nil {
+		return nil, err
+	}
+	if value == nil || value.Sign() < 0 {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAmount, value)
+	}
+	result := new(big.Int).Add(balance, value)
+	if result.Cmp(MaxUint256) > 0 {
+		return nil, ErrBalanceOverflow
+	}
+	return result, nil
+}
+
+func debit(balance *big.Int, value *big.Int) (*big.Int, error) {
+	if err := checkStored(balance); err != nil {
+		return nil, err
+	}
+	if value == nil || value.Sign() < 0 {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAmount, value)
+	}
+	result := new(big.Int).Sub(balance, value)
+	if result.Sign() < 0 {
+		return nil, fmt.Errorf("%w: balance %s, requested %s", ErrInsufficientFunds, balance, value)
+	}
+	return result, nil
+}
+
+func checkStored(balance *big.Int) error {
+	if balance == nil || balance.Sign() < 0 || balance.Cmp(MaxUint256) > 0 {
+		return fmt.Errorf("%w: stored balance %v is not a uint256", ErrCorruptedState, balance)
+	}
+	return nil
+}
+
+// encodeValue renders a voucher value as the 32-byte hex word the rollup
+// server expects.
+func encodeValue(value *big.Int) string {
+	return hexutil.Encode(common.LeftPadBytes(value.Bytes(), 32))
+}
+
+var (
+	erc20ABI  = mustParseABI("transfer(address,uint256)")
+	erc721ABI = mustParseABI("safeTransferFrom(address,address,uint256)")
+)
+
+func mustParseABI(signature string) abi.ABI {
+	method, err := rollups.ParseSignature(signature)
+	if err != nil {
+		panic(err)
+	}
+	return abi.ABI{Methods: map[string]abi.Method{method.Name: method}}
+}
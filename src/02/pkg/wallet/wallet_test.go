@@ -0,0 +1,129 @@
+package wallet
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/henriquemarlon/cartesi-golang-series/src/02/pkg/rollups"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	alice       = common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	bob         = common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+	token       = common.HexToAddress("0xFBdB734EF6a23aD76863CbA6f10d0C5CBBD8342C")
+	application = common.HexToAddress("0xab7528bb862fb57e8a2bcd567a2e929a0be56a5e")
+)
+
+func TestWalletSuite(t *testing.T) {
+	suite.Run(t, new(WalletSuite))
+}
+
+type WalletSuite struct {
+	suite.Suite
+	wallet *Wallet
+}
+
+func (s *WalletSuite) SetupTest() {
+	s.wallet = NewWallet(NewMemoryStore())
+}
+
+func (s *WalletSuite) balance(owner common.Address) *big.Int {
+	balance, err := s.wallet.EtherBalanceOf(owner)
+	s.Require().NoError(err)
+	return balance
+}
+
+func (s *WalletSuite) TestEtherDepositAndTransfer() {
+	s.NoError(s.wallet.Deposit(&rollups.EtherDeposit{Sender: alice, Value: big.NewInt(100)}))
+	s.NoError(s.wallet.Deposit(&rollups.EtherDeposit{Sender: alice, Value: big.NewInt(50)}))
+	s.NoError(s.wallet.EtherTransfer(alice, bob, big.NewInt(120)))
+	s.Equal(big.NewInt(30), s.balance(alice))
+	s.Equal(big.NewInt(120), s.balance(bob))
+}
+
+func (s *WalletSuite) TestInsufficientFunds() {
+	s.NoError(s.wallet.Deposit(&rollups.EtherDeposit{Sender: alice, Value: big.NewInt(10)}))
+	err := s.wallet.EtherTransfer(alice, bob, big.NewInt(11))
+	s.True(errors.Is(err, ErrInsufficientFunds))
+	s.Equal(big.NewInt(10), s.balance(alice))
+	s.Equal(big.NewInt(0), s.balance(bob))
+}
+
+func (s *WalletSuite) TestNegativeAmount() {
+	s.NoError(s.wallet.Deposit(&rollups.EtherDeposit{Sender: alice, Value: big.NewInt(10)}))
+	err := s.wallet.EtherTransfer(alice, bob, big.NewInt(-5))
+	s.True(errors.Is(err, ErrInvalidAmount))
+}
+
+func (s *WalletSuite) TestOverflow() {
+	s.NoError(s.wallet.Deposit(&rollups.ERC20Deposit{Token: token, Sender: alice, Value: big.NewInt(1)}))
+	s.NoError(s.wallet.Deposit(&rollups.ERC20Deposit{Token: token, Sender: bob, Value: MaxUint256}))
+	err := s.wallet.ERC20Transfer(token, alice, bob, big.NewInt(1))
+	s.True(errors.Is(err, ErrBalanceOverflow))
+
+	err = s.wallet.Deposit(&rollups.ERC20Deposit{Token: token, Sender: bob, Value: big.NewInt(1)})
+	s.True(errors.Is(err, ErrBalanceOverflow))
+}
+
+func (s *WalletSuite) TestEtherWithdraw() {
+	s.NoError(s.wallet.Deposit(&rollups.EtherDeposit{Sender: alice, Value: big.NewInt(100)}))
+	voucher, err := s.wallet.EtherWithdraw(alice, big.NewInt(40))
+	s.Require().NoError(err)
+	s.Equal(alice.Hex(), voucher.Destination)
+	s.Equal("0x0000000000000000000000000000000000000000000000000000000000000028", voucher.Value)
+	s.Equal("0x", voucher.Payload)
+	s.Equal(big.NewInt(60), s.balance(alice))
+}
+
+func (s *WalletSuite) TestERC20Withdraw() {
+	s.NoError(s.wallet.Deposit(&rollups.ERC20Deposit{Token: token, Sender: alice, Value: big.NewInt(10000)}))
+	voucher, err := s.wallet.ERC20Withdraw(token, alice, big.NewInt(10000))
+	s.Require().NoError(err)
+	s.Equal(token.Hex(), voucher.Destination)
+
+	expected := []byte{0xa9, 0x05, 0x9c, 0xbb}
+	expected = append(expected, common.LeftPadBytes(alice[:], 32)...)
+	expected = append(expected, big.NewInt(10000).FillBytes(make([]byte, 32))...)
+	s.Equal(hexutil.Encode(expected), voucher.Payload)
+
+	_, err = s.wallet.ERC20Withdraw(token, alice, big.NewInt(1))
+	s.True(errors.Is(err, ErrInsufficientFunds))
+}
+
+func (s *WalletSuite) TestERC721TransferAndWithdraw() {
+	s.NoError(s.wallet.Deposit(&rollups.ERC721Deposit{Token: token, Sender: alice, TokenId: big.NewInt(1)}))
+
+	err := s.wallet.ERC721Transfer(token, bob, alice, big.NewInt(1))
+	s.True(errors.Is(err, ErrNotTokenOwner))
+	s.NoError(s.wallet.ERC721Transfer(token, alice, bob, big.NewInt(1)))
+
+	voucher, err := s.wallet.ERC721Withdraw(application, token, bob, big.NewInt(1))
+	s.Require().NoError(err)
+	s.Equal(token.Hex(), voucher.Destination)
+
+	expected := []byte{0x42, 0x84, 0x2e, 0x0e}
+	expected = append(expected, common.LeftPadBytes(application[:], 32)...)
+	expected = append(expected, common.LeftPadBytes(bob[:], 32)...)
+	expected = append(expected, big.NewInt(1).FillBytes(make([]byte, 32))...)
+	s.Equal(hexutil.Encode(expected), voucher.Payload)
+
+	owner, err := s.wallet.ERC721OwnerOf(token, big.NewInt(1))
+	s.NoError(err)
+	s.Equal(common.Address{}, owner)
+}
+
+func (s *WalletSuite) TestUnsupportedDeposit() {
+	err := s.wallet.Deposit(&rollups.ERC1155SingleDeposit{Token: token, Sender: alice})
+	s.True(errors.Is(err, ErrUnsupportedAsset))
+}
+
+func (s *WalletSuite) TestCorruptedState() {
+	store := NewMemoryStore()
+	s.NoError(store.UpdateEtherBalance(alice, big.NewInt(-1)))
+	err := NewWallet(store).EtherTransfer(alice, bob, big.NewInt(1))
+	s.True(errors.Is(err, ErrCorruptedState))
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log/slog"
 	"math/big"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -26,7 +28,57 @@ var (
 	emergencyWithdrawAddress = common.HexToAddress("0xA716b0bE3a59b05A307b98c6bAf9d21dF796F37d")
 )
 
-type Application struct{}
+const (
+	roleAdmin    = "admin"
+	roleOperator = "operator"
+	roleUser     = "user"
+)
+
+// guardedPaths maps the paths that move the application's own funds or
+// change roles to the role their msg_sender must hold.
+var guardedPaths = map[string]string{
+	"grant_role":               roleAdmin,
+	"revoke_role":              roleAdmin,
+	"emergency_erc20_withdraw": roleAdmin,
+	"emergency_eth_withdraw":   roleAdmin,
+}
+
+type Application struct {
+	// roles holds, for each role, the accounts it is granted to.
+	roles map[string]map[common.Address]bool
+}
+
+// NewApplication returns an application whose admins are the given
+// accounts. Further roles are granted through the grant_role path.
+func NewApplication(admins ...common.Address) *Application {
+	a := &Application{}
+	for _, admin := range admins {
+		a.grantRole(admin, roleAdmin)
+	}
+	return a
+}
+
+func (a *Application) hasRole(account common.Address, role string) bool {
+	return a.roles[role][account]
+}
+
+func (a *Application) grantRole(account common.Address, role string) {
+	if a.roles == nil {
+		a.roles = make(map[string]map[common.Address]bool)
+	}
+	if a.roles[role] == nil {
+		a.roles[role] = make(map[common.Address]bool)
+	}
+	a.roles[role][account] = true
+}
+
+func (a *Application) revokeRole(account common.Address, role string) error {
+	if role == roleAdmin && a.hasRole(account, roleAdmin) && len(a.roles[roleAdmin]) == 1 {
+		return fmt.Errorf("cannot revoke the last admin")
+	}
+	delete(a.roles[role], account)
+	return nil
+}
 
 func (a *Application) Advance(
 	env rollmelette.Env,
@@ -52,7 +104,34 @@ func (a *Application) Advance(
 		return fmt.Errorf("failed to validate input: %w", err)
 	}
 
+	if role, ok := guardedPaths[input.Path]; ok && !a.hasRole(metadata.MsgSender, role) {
+		env.Report([]byte(fmt.Sprintf("Forbidden: %s requires the %s role", input.Path, role)))
+		return fmt.Errorf("%s lacks the %s role required by %s", metadata.MsgSender, role, input.Path)
+	}
+
 	switch input.Path {
+	case "grant_role", "revoke_role":
+		var data struct {
+			Account common.Address `json:"account" validate:"required"`
+			Role    string         `json:"role" validate:"required,oneof=admin operator user"`
+		}
+		if err := json.Unmarshal(input.Data, &data); err != nil {
+			return err
+		}
+		if err := validator.Struct(data); err != nil {
+			return fmt.Errorf("failed to validate input: %w", err)
+		}
+		if input.Path == "grant_role" {
+			a.grantRole(data.Account, data.Role)
+			env.Notice([]byte(fmt.Sprintf("Role %s granted to %s", data.Role, data.Account)))
+			return nil
+		}
+		if err := a.revokeRole(data.Account, data.Role); err != nil {
+			return err
+		}
+		env.Notice([]byte(fmt.Sprintf("Role %s revoked from %s", data.Role, data.Account)))
+		return nil
+
 	case "deploy_nft":
 		var data struct {
 			Name   string `json:"name" validate:"required"`
@@ -211,6 +290,29 @@ func (a *Application) Inspect(env rollmelette.EnvInspector, payload []byte) erro
 		env.Report([]byte(contractsJson))
 		return nil
 
+	case "roles":
+		type assignment struct {
+			Account common.Address `json:"account"`
+			Role    string         `json:"role"`
+		}
+		assignments := []assignment{}
+		for _, role := range []string{roleAdmin, roleOperator, roleUser} {
+			accounts := make([]common.Address, 0, len(a.roles[role]))
+			for account := range a.roles[role] {
+				accounts = append(accounts, account)
+			}
+			slices.SortFunc(accounts, func(x, y common.Address) int { return x.Cmp(y) })
+			for _, account := range accounts {
+				assignments = append(assignments, assignment{Account: account, Role: role})
+			}
+		}
+		rolesJson, err := json.Marshal(assignments)
+		if err != nil {
+			return err
+		}
+		env.Report(rolesJson)
+		return nil
+
 	case "erc20_balance":
 		var data struct {
 			Token   common.Address `json:"token" validate:"required"`
@@ -414,10 +516,35 @@ func buildEmergencyETHWithdrawPayload(to common.Address) ([]byte, error) {
 	return payload, nil
 }
 
+// parseAccounts parses a comma-separated list of addresses. It follows
+// acl.ParseAccounts in src/02, which this module cannot import because it is
+// built on its own by cartesi build.
+func parseAccounts(value string) ([]common.Address, error) {
+	var accounts []common.Address
+	for _, address := range strings.Split(value, ",") {
+		address = strings.TrimSpace(address)
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid account %q", address)
+		}
+		accounts = append(accounts, common.HexToAddress(address))
+	}
+	return accounts, nil
+}
+
 func main() {
 	ctx := context.Background()
 	opts := rollmelette.NewRunOpts()
-	app := new(Application)
+	// ADMIN_ADDRESSES is a comma-separated list of the accounts allowed to
+	// call the guarded paths and to grant roles.
+	var admins []common.Address
+	if value := os.Getenv("ADMIN_ADDRESSES"); value != "" {
+		var err error
+		if admins, err = parseAccounts(value); err != nil {
+			slog.Error("invalid ADMIN_ADDRESSES", "error", err)
+			os.Exit(1)
+		}
+	}
+	app := NewApplication(admins...)
 	err := rollmelette.Run(ctx, opts, app)
 	if err != nil {
 		slog.Error("application error", "error", err)
@@ -26,7 +26,7 @@ type ApplicationSuite struct {
 }
 
 func (s *ApplicationSuite) SetupTest() {
-	app := new(Application)
+	app := NewApplication(msgSender)
 	s.tester = rollmelette.NewTester(app)
 }
 
@@ -200,7 +200,7 @@ func (s *ApplicationSuite) TestDelegateCallVoucherEmergencyERC20Withdraw() {
 	token := common.HexToAddress("0xfafafafafafafafafafafafafafafafafafafafa")
 
 	emergencyERC20WithdrawInput := []byte(fmt.Sprintf(`{"path":"emergency_erc20_withdraw","data":{"token":"%s","to":"%s"}}`, token, to))
-	emergencyERC20WithdrawOutput := s.tester.Advance(to, emergencyERC20WithdrawInput)
+	emergencyERC20WithdrawOutput := s.tester.Advance(msgSender, emergencyERC20WithdrawInput)
 	s.Nil(emergencyERC20WithdrawOutput.Err)
 	s.Len(emergencyERC20WithdrawOutput.DelegateCallVouchers, 1)
 	s.Equal(emergencyWithdrawAddress, emergencyERC20WithdrawOutput.DelegateCallVouchers[0].Destination)
@@ -226,7 +226,7 @@ func (s *ApplicationSuite) TestDelegateCallVoucherEmergencyETHWithdraw() {
 	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
 
 	emergencyETHWithdrawInput := []byte(fmt.Sprintf(`{"path":"emergency_eth_withdraw","data":{"to":"%s"}}`, to))
-	emergencyETHWithdrawOutput := s.tester.Advance(to, emergencyETHWithdrawInput)
+	emergencyETHWithdrawOutput := s.tester.Advance(msgSender, emergencyETHWithdrawInput)
 	s.Nil(emergencyETHWithdrawOutput.Err)
 	s.Len(emergencyETHWithdrawOutput.DelegateCallVouchers, 1)
 	s.Equal(emergencyWithdrawAddress, emergencyETHWithdrawOutput.DelegateCallVouchers[0].Destination)
@@ -246,6 +246,46 @@ func (s *ApplicationSuite) TestDelegateCallVoucherEmergencyETHWithdraw() {
 	s.Equal(to, unpacked[0].(common.Address))
 }
 
+func (s *ApplicationSuite) TestEmergencyWithdrawRequiresAdmin() {
+	operator := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	emergencyETHWithdrawInput := []byte(fmt.Sprintf(`{"path":"emergency_eth_withdraw","data":{"to":"%s"}}`, operator))
+
+	output := s.tester.Advance(operator, emergencyETHWithdrawInput)
+	s.ErrorContains(output.Err, "lacks the admin role")
+	s.Empty(output.DelegateCallVouchers)
+
+	grantInput := []byte(fmt.Sprintf(`{"path":"grant_role","data":{"account":"%s","role":"admin"}}`, operator))
+	s.ErrorContains(s.tester.Advance(operator, grantInput).Err, "lacks the admin role")
+	s.Nil(s.tester.Advance(msgSender, grantInput).Err)
+
+	output = s.tester.Advance(operator, emergencyETHWithdrawInput)
+	s.Nil(output.Err)
+	s.Len(output.DelegateCallVouchers, 1)
+
+	revokeInput := []byte(fmt.Sprintf(`{"path":"revoke_role","data":{"account":"%s","role":"admin"}}`, operator))
+	s.Nil(s.tester.Advance(msgSender, revokeInput).Err)
+	s.ErrorContains(s.tester.Advance(operator, emergencyETHWithdrawInput).Err, "lacks the admin role")
+}
+
+func (s *ApplicationSuite) TestRevokeLastAdmin() {
+	revokeInput := []byte(fmt.Sprintf(`{"path":"revoke_role","data":{"account":"%s","role":"admin"}}`, msgSender))
+	s.ErrorContains(s.tester.Advance(msgSender, revokeInput).Err, "last admin")
+}
+
+func (s *ApplicationSuite) TestInspectRoles() {
+	operator := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	grantInput := []byte(fmt.Sprintf(`{"path":"grant_role","data":{"account":"%s","role":"operator"}}`, operator))
+	s.Nil(s.tester.Advance(msgSender, grantInput).Err)
+
+	output := s.tester.Inspect([]byte(`{"path":"roles"}`))
+	s.Nil(output.Err)
+	s.Require().Len(output.Reports, 1)
+	s.JSONEq(fmt.Sprintf(
+		`[{"account":"%s","role":"admin"},{"account":"%s","role":"operator"}]`,
+		strings.ToLower(msgSender.Hex()), strings.ToLower(operator.Hex()),
+	), string(output.Reports[0].Payload))
+}
+
 func (s *ApplicationSuite) TestInspectContracts() {
 	applicationAddress := common.HexToAddress("0xab7528bb862fb57e8a2bcd567a2e929a0be56a5e")
 